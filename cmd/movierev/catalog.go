@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/mayurvarma14/go-movie-review/controllers"
+	"github.com/mayurvarma14/go-movie-review/database"
+	"github.com/mayurvarma14/go-movie-review/dto"
+	"github.com/mayurvarma14/go-movie-review/helpers"
+	"github.com/mayurvarma14/go-movie-review/internals/config"
+	"github.com/spf13/cobra"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// importFormats maps file extensions to the media types imports accept.
+var importFormats = map[string]string{
+	".csv":    helpers.CSVContentType,
+	".ndjson": helpers.NDJSONContentType,
+	".jsonl":  helpers.NDJSONContentType,
+}
+
+func newImportCommand() *cobra.Command {
+	var dryRun bool
+	cmd := &cobra.Command{
+		Use:   "import movies|genres FILE",
+		Short: "Create and update catalog entries from a CSV or NDJSON file",
+		Long: "Create and update movies or genres from a CSV (.csv) or NDJSON (.ndjson, .jsonl)\n" +
+			"file in the format POST /movies/import and /genres/import take. Rows that fail are\n" +
+			"listed and make the command exit non-zero; the others are still imported.",
+		Args:      cobra.ExactArgs(2),
+		ValidArgs: []string{"movies", "genres"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kind, path := args[0], args[1]
+			if kind != "movies" && kind != "genres" {
+				return fmt.Errorf("unknown catalog %q, expected movies or genres", kind)
+			}
+			mediaType, ok := importFormats[strings.ToLower(filepath.Ext(path))]
+			if !ok {
+				return fmt.Errorf("%s: imports must be .csv, .ndjson or .jsonl files", path)
+			}
+
+			return withDatabase(cmd.Context(), true, func(cfg *config.Config, db *database.Database) error {
+				binding.EnableDecoderDisallowUnknownFields = cfg.Server.StrictJSON
+
+				file, err := os.Open(path)
+				if err != nil {
+					return err
+				}
+				defer file.Close()
+
+				var report dto.ImportReport
+				if kind == "movies" {
+					report, err = importFile(cmd.Context(), file, mediaType, dto.MovieImportColumns, dto.NewMovieImportRow, controllers.NewMovieController(db).ImportRows, dryRun)
+				} else {
+					report, err = importFile(cmd.Context(), file, mediaType, dto.GenreImportColumns, dto.NewGenreImportRow, controllers.NewGenreController(db).ImportRows, dryRun)
+				}
+				if err != nil {
+					return err
+				}
+				return printImportReport(cmd.OutOrStdout(), report)
+			})
+		},
+	}
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "report what would happen without writing anything")
+	return cmd
+}
+
+func importFile[T any](ctx context.Context, file io.Reader, mediaType string, columns []string, fromCSV func(map[string]string) (T, error),
+	importRows func(context.Context, []helpers.ImportRow[T], bool, helpers.AuditActor) (dto.ImportReport, error), dryRun bool,
+) (dto.ImportReport, error) {
+	rows, err := helpers.DecodeImport(file, mediaType, columns, fromCSV)
+	if err != nil {
+		return dto.ImportReport{}, err
+	}
+	return importRows(ctx, rows, dryRun, cliActor())
+}
+
+func printImportReport(out io.Writer, report dto.ImportReport) error {
+	for _, row := range report.Rows {
+		if row.Status == dto.ImportFailed {
+			fmt.Fprintf(out, "line %d: %s\n", row.Line, row.Error)
+		}
+	}
+	summary := fmt.Sprintf("created %d, updated %d, skipped %d, failed %d", report.Created, report.Updated, report.Skipped, report.Failed)
+	if report.DryRun {
+		summary += " (dry run, nothing written)"
+	}
+	fmt.Fprintln(out, summary)
+
+	if report.Failed > 0 {
+		return errors.New("some rows were not imported")
+	}
+	return nil
+}
+
+// catalogExports write one kind of catalog entry in the order the export
+// endpoints use.
+var catalogExports = map[string]func(ctx context.Context, db *database.Database, out io.Writer, format string) (int, error){
+	"movies": func(ctx context.Context, db *database.Database, out io.Writer, format string) (int, error) {
+		return exportCollection(ctx, db.OpenCollection("movie"), "movie_id", out, format, dto.MovieExportColumns, dto.NewMovieResponse)
+	},
+	"genres": func(ctx context.Context, db *database.Database, out io.Writer, format string) (int, error) {
+		return exportCollection(ctx, db.OpenCollection("genre"), "genre_id", out, format, dto.GenreExportColumns, dto.NewGenreResponse)
+	},
+	"reviews": func(ctx context.Context, db *database.Database, out io.Writer, format string) (int, error) {
+		return exportCollection(ctx, db.OpenCollection("review"), "_id", out, format, dto.ReviewExportColumns, dto.NewReviewResponse)
+	},
+}
+
+func newExportCommand() *cobra.Command {
+	var format, output string
+	cmd := &cobra.Command{
+		Use:       "export movies|genres|reviews",
+		Short:     "Write catalog entries as CSV, JSON or NDJSON",
+		Long:      "Write every movie, genre or review that is not in the trash, as the export endpoints do.",
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: []string{"movies", "genres", "reviews"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			export, ok := catalogExports[args[0]]
+			if !ok {
+				return fmt.Errorf("unknown catalog %q, expected movies, genres or reviews", args[0])
+			}
+			if _, err := helpers.ParseExportFormat(format); err != nil {
+				return err
+			}
+
+			return withDatabase(cmd.Context(), false, func(cfg *config.Config, db *database.Database) error {
+				out := cmd.OutOrStdout()
+				var file *os.File
+				if output != "" && output != "-" {
+					var err error
+					if file, err = os.Create(output); err != nil {
+						return err
+					}
+					defer file.Close()
+					out = file
+				}
+
+				count, err := export(cmd.Context(), db, out, format)
+				if err != nil {
+					return fmt.Errorf("export stopped after %d %s: %w", count, args[0], err)
+				}
+				if file != nil {
+					if err := file.Close(); err != nil {
+						return err
+					}
+				}
+				fmt.Fprintf(cmd.ErrOrStderr(), "exported %d %s\n", count, args[0])
+				return nil
+			})
+		},
+	}
+	cmd.Flags().StringVarP(&format, "format", "f", helpers.ExportJSON, "csv, json or ndjson")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "file to write, stdout by default")
+	return cmd
+}
+
+func exportCollection[M any, R any](ctx context.Context, collection *mongo.Collection, sortKey string, out io.Writer, format string, columns []string, toRow func(*M) R) (int, error) {
+	findOptions := options.Find().SetSort(bson.D{{Key: sortKey, Value: 1}})
+	cursor, err := collection.Find(ctx, helpers.NotDeleted(bson.M{}), findOptions)
+	if err != nil {
+		return 0, fmt.Errorf("finding %s: %w", collection.Name(), err)
+	}
+	defer cursor.Close(ctx)
+
+	return helpers.WriteExport(ctx, out, cursor, format, columns, toRow)
+}
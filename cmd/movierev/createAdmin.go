@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bufio"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+
+	"github.com/mayurvarma14/go-movie-review/controllers"
+	"github.com/mayurvarma14/go-movie-review/database"
+	"github.com/mayurvarma14/go-movie-review/dto"
+	"github.com/mayurvarma14/go-movie-review/helpers"
+	"github.com/mayurvarma14/go-movie-review/internals/apperrors"
+	"github.com/mayurvarma14/go-movie-review/internals/config"
+	"github.com/spf13/cobra"
+)
+
+const passwordAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz23456789!@#$%^&*-_=+"
+
+func newCreateAdminCommand() *cobra.Command {
+	var req dto.SignUpRequest
+	var passwordStdin bool
+	cmd := &cobra.Command{
+		Use:   "create-admin",
+		Short: "Create an admin account",
+		Long: "Create an admin account, subject to the same rules as sign-up. The password is read\n" +
+			"from stdin with --password-stdin; otherwise one that meets the policy is generated\n" +
+			"and printed once.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withDatabase(cmd.Context(), true, func(cfg *config.Config, db *database.Database) error {
+				req.UserType = helpers.AdminRole
+				generated := !passwordStdin
+				var err error
+				if passwordStdin {
+					req.Password, err = readPassword(cmd.InOrStdin())
+				} else {
+					req.Password, err = generatePassword(cfg.Password, req.Username, req.Email)
+				}
+				if err != nil {
+					return err
+				}
+
+				user, err := controllers.NewUserController(db, cfg).CreateUser(cmd.Context(), req)
+				if err != nil {
+					return describeError(err)
+				}
+				helpers.RecordAuditsAs(cmd.Context(), cliActor(), db.OpenCollection("audit"), "user", []helpers.AuditChange{
+					{Action: helpers.AuditCreate, ResourceID: user.UserID, After: dto.NewUserResponse(&user)},
+				})
+
+				fmt.Fprintf(cmd.OutOrStdout(), "created admin %s with user_id %s\n", req.Username, user.UserID)
+				if generated {
+					fmt.Fprintf(cmd.OutOrStdout(), "password: %s\n", req.Password)
+				}
+				return nil
+			})
+		},
+	}
+	cmd.Flags().StringVar(&req.Name, "name", "", "display name")
+	cmd.Flags().StringVar(&req.Username, "username", "", "username")
+	cmd.Flags().StringVar(&req.Email, "email", "", "email address, used to log in")
+	cmd.Flags().BoolVar(&passwordStdin, "password-stdin", false, "read the password from the first line of stdin")
+	for _, name := range []string{"name", "username", "email"} {
+		_ = cmd.MarkFlagRequired(name)
+	}
+	return cmd
+}
+
+func readPassword(in io.Reader) (string, error) {
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("reading password: %w", err)
+	}
+	password := strings.TrimRight(line, "\r\n")
+	if password == "" {
+		return "", errors.New("no password on stdin")
+	}
+	return password, nil
+}
+
+// generatePassword draws random passwords until one satisfies the policy,
+// which a long enough one almost always does on the first try.
+func generatePassword(cfg config.PasswordConfig, username, email string) (string, error) {
+	length := max(cfg.MinLength, 20)
+	if cfg.MaxLength > 0 {
+		length = min(length, cfg.MaxLength)
+	}
+	policy := helpers.NewPasswordPolicy(cfg)
+	limit := big.NewInt(int64(len(passwordAlphabet)))
+
+	for range 100 {
+		password := make([]byte, length)
+		for i := range password {
+			n, err := rand.Int(rand.Reader, limit)
+			if err != nil {
+				return "", fmt.Errorf("generating password: %w", err)
+			}
+			password[i] = passwordAlphabet[n.Int64()]
+		}
+		if policy.Validate(string(password), username, email) == nil {
+			return string(password), nil
+		}
+	}
+	return "", errors.New("could not generate a password that meets the policy, use --password-stdin")
+}
+
+// describeError spells out invalid input field by field, as the API's error
+// responses do.
+func describeError(err error) error {
+	var appErr *apperrors.Error
+	if errors.As(err, &appErr) && appErr.Kind == apperrors.KindValidation && appErr.Err != nil {
+		return errors.New(helpers.ImportError(appErr.Err))
+	}
+	return err
+}
@@ -0,0 +1,106 @@
+// Command movierev runs the movie review API and the tasks that administer
+// it from a shell: migrations, admin accounts, catalog imports and exports,
+// reindexing, trash purges, key rotation and session revocation. Every
+// command reads the same configuration as the server.
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"os/user"
+	"syscall"
+
+	"github.com/mayurvarma14/go-movie-review/database"
+	"github.com/mayurvarma14/go-movie-review/helpers"
+	"github.com/mayurvarma14/go-movie-review/internals/config"
+	"github.com/mayurvarma14/go-movie-review/internals/logger"
+	"github.com/spf13/cobra"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	// A second signal during shutdown kills the process.
+	context.AfterFunc(ctx, stop)
+
+	if err := newRootCommand().ExecuteContext(ctx); err != nil {
+		os.Exit(1)
+	}
+}
+
+func newRootCommand() *cobra.Command {
+	root := &cobra.Command{
+		Use:          "movierev",
+		Short:        "Run and administer the movie review API",
+		SilenceUsage: true,
+	}
+	root.AddCommand(
+		newServeCommand(),
+		newMigrateCommand(),
+		newCreateAdminCommand(),
+		newImportCommand(),
+		newExportCommand(),
+		newReindexCommand(),
+		newPurgeTrashCommand(),
+		newRotateKeysCommand(),
+		newRevokeSessionsCommand(),
+	)
+	return root
+}
+
+// loadConfig loads the configuration and installs a logger writing to
+// stderr, which keeps logs out of output such as an export on stdout.
+func loadConfig() (*config.Config, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, err
+	}
+	slog.SetDefault(logger.New(os.Stderr, cfg.Log.Level, cfg.Log.Format))
+	return cfg, nil
+}
+
+// withDatabase connects to MongoDB for the length of fn. Commands that change
+// data refuse to run against a database with pending migrations, since the
+// indexes they rely on may be missing.
+func withDatabase(ctx context.Context, requireMigrated bool, fn func(cfg *config.Config, db *database.Database) error) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	db, err := database.New(ctx, cfg.Mongo)
+	if err != nil {
+		return fmt.Errorf("database init failed: %w", err)
+	}
+	defer func() {
+		disconnectCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+		defer cancel()
+		if err := db.Client.Disconnect(disconnectCtx); err != nil {
+			slog.Error("failed to disconnect from MongoDB", "error", err)
+		}
+	}()
+
+	if requireMigrated {
+		pending, err := db.PendingMigrations(ctx)
+		if err != nil {
+			return err
+		}
+		if len(pending) > 0 {
+			return fmt.Errorf("database has pending migrations %v, run movierev migrate first", pending)
+		}
+	}
+	return fn(cfg, db)
+}
+
+// cliActor attributes audited writes to the operating system user running
+// the command.
+func cliActor() helpers.AuditActor {
+	name := "unknown"
+	if u, err := user.Current(); err == nil {
+		name = u.Username
+	}
+	return helpers.AuditActor{ID: "cli:" + name}
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/mayurvarma14/go-movie-review/database"
+	"github.com/mayurvarma14/go-movie-review/internals/config"
+	"github.com/spf13/cobra"
+)
+
+func newMigrateCommand() *cobra.Command {
+	var status bool
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Apply pending database migrations",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withDatabase(cmd.Context(), false, func(cfg *config.Config, db *database.Database) error {
+				if !status {
+					if err := db.Migrate(cmd.Context()); err != nil {
+						return err
+					}
+				}
+
+				pending, err := db.PendingMigrations(cmd.Context())
+				if err != nil {
+					return err
+				}
+				if len(pending) == 0 {
+					fmt.Fprintln(cmd.OutOrStdout(), "database is up to date")
+					return nil
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "pending migrations: %v\n", pending)
+				return nil
+			})
+		},
+	}
+	cmd.Flags().BoolVar(&status, "status", false, "list pending migrations without applying them")
+	return cmd
+}
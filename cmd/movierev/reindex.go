@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/mayurvarma14/go-movie-review/database"
+	"github.com/mayurvarma14/go-movie-review/internals/config"
+	"github.com/spf13/cobra"
+)
+
+func newReindexCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "reindex",
+		Short: "Recreate catalog indexes and recount each movie's reviews",
+		Long: "Recreate any missing movie, genre and review indexes that migrations define, then\n" +
+			"recount the reviews outside the trash for every movie and correct review_count\n" +
+			"where it drifted. Reviews written while it runs may need another pass.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withDatabase(cmd.Context(), true, func(cfg *config.Config, db *database.Database) error {
+				if err := db.EnsureCatalogIndexes(cmd.Context()); err != nil {
+					return err
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), "catalog indexes are in place")
+
+				corrected, err := db.RecountReviews(cmd.Context())
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "corrected the review count of %d movies\n", corrected)
+				return nil
+			})
+		},
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/mayurvarma14/go-movie-review/database"
+	"github.com/mayurvarma14/go-movie-review/helpers"
+	"github.com/mayurvarma14/go-movie-review/internals/config"
+	"github.com/spf13/cobra"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func newRotateKeysCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rotate-keys",
+		Short: "Generate a new SECRET_KEY",
+		Long: "Generate a new signing key and print it as SECRET_KEY. Nothing else changes until the\n" +
+			"server restarts with it. Move the current key to PREVIOUS_SECRET_KEY at the same time,\n" +
+			"and tokens signed with it keep working until they expire, so nobody is signed out;\n" +
+			"remove PREVIOUS_SECRET_KEY once REFRESH_TOKEN_TTL has passed. If the old key leaked,\n" +
+			"leave PREVIOUS_SECRET_KEY unset instead and run revoke-sessions after the restart.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key := make([]byte, 32)
+			if _, err := rand.Read(key); err != nil {
+				return fmt.Errorf("generating key: %w", err)
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "SECRET_KEY=%s\n", base64.RawURLEncoding.EncodeToString(key))
+			fmt.Fprintln(cmd.ErrOrStderr(), "move the current SECRET_KEY to PREVIOUS_SECRET_KEY, set the new one and restart the server;\n"+
+				"remove PREVIOUS_SECRET_KEY after REFRESH_TOKEN_TTL, or skip it and run movierev revoke-sessions if the old key leaked")
+			return nil
+		},
+	}
+}
+
+func newRevokeSessionsCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "revoke-sessions",
+		Short: "Revoke every session so all users sign in again",
+		Long: "Delete every session, which ends all refresh tokens and signs everyone out once their\n" +
+			"access tokens expire. After a key rotation, run it only once the server is signing\n" +
+			"with the new key, or users signing in between would be issued tokens under the old one.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withDatabase(cmd.Context(), true, func(cfg *config.Config, db *database.Database) error {
+				result, err := db.OpenCollection("session").DeleteMany(cmd.Context(), bson.M{})
+				if err != nil {
+					return fmt.Errorf("revoking sessions: %w", err)
+				}
+				helpers.RecordAuditsAs(cmd.Context(), cliActor(), db.OpenCollection("audit"), "session", []helpers.AuditChange{
+					{Action: helpers.AuditDelete, ResourceID: "*", After: bson.M{"revoked": result.DeletedCount}},
+				})
+				fmt.Fprintf(cmd.OutOrStdout(), "revoked %d sessions\n", result.DeletedCount)
+				return nil
+			})
+		},
+	}
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"github.com/mayurvarma14/go-movie-review/internals/config"
+	"github.com/mayurvarma14/go-movie-review/internals/logger"
+	"github.com/mayurvarma14/go-movie-review/internals/server"
+	"github.com/spf13/cobra"
+)
+
+func newServeCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "serve",
+		Short: "Serve the HTTP API, applying pending migrations first",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := config.Load()
+			if err != nil {
+				return err
+			}
+			logger.Setup(cfg.Log)
+			return server.Run(cmd.Context(), cfg)
+		},
+	}
+}
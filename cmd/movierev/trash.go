@@ -0,0 +1,44 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mayurvarma14/go-movie-review/database"
+	"github.com/mayurvarma14/go-movie-review/helpers"
+	"github.com/mayurvarma14/go-movie-review/internals/config"
+	"github.com/spf13/cobra"
+)
+
+func newPurgeTrashCommand() *cobra.Command {
+	var olderThan time.Duration
+	cmd := &cobra.Command{
+		Use:   "purge-trash",
+		Short: "Permanently remove soft-deleted movies, genres and reviews",
+		Long: "Permanently remove soft-deleted movies, genres and reviews that have been in the\n" +
+			"trash longer than --older-than, which defaults to TRASH_RETENTION. --older-than 0\n" +
+			"empties the trash. Every purged document is recorded in the audit log first.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withDatabase(cmd.Context(), true, func(cfg *config.Config, db *database.Database) error {
+				if !cmd.Flags().Changed("older-than") {
+					if cfg.Trash.Retention <= 0 {
+						return errors.New("TRASH_RETENTION keeps the trash forever, pass --older-than to purge anyway")
+					}
+					olderThan = cfg.Trash.Retention
+				}
+
+				purged, err := db.PurgeTrash(cmd.Context(), time.Now().Add(-olderThan), helpers.PurgeAuditor(cliActor(), db.OpenCollection("audit")))
+				for _, name := range database.TrashCollections {
+					if count, ok := purged[name]; ok {
+						fmt.Fprintf(cmd.OutOrStdout(), "purged %d from %s\n", count, name)
+					}
+				}
+				return err
+			})
+		},
+	}
+	cmd.Flags().DurationVar(&olderThan, "older-than", 0, "purge documents deleted longer ago than this")
+	return cmd
+}
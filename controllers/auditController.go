@@ -0,0 +1,101 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mayurvarma14/go-movie-review/database"
+	"github.com/mayurvarma14/go-movie-review/dto"
+	"github.com/mayurvarma14/go-movie-review/helpers"
+	"github.com/mayurvarma14/go-movie-review/internals/apperrors"
+	"github.com/mayurvarma14/go-movie-review/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// AuditController reads the audit log. Entries are written by the handlers
+// that perform administrative actions and are never changed afterwards, so
+// it offers no way to modify them.
+type AuditController struct {
+	auditCollection *mongo.Collection
+}
+
+func NewAuditController(db *database.Database) *AuditController {
+	return &AuditController{
+		auditCollection: db.Client.Database(db.Name).Collection("audit"),
+	}
+}
+
+// GetAuditLog lists audit entries, newest first, optionally narrowed to one
+// actor, one resource type or id, and a time range given as RFC 3339 bounds.
+func (ac *AuditController) GetAuditLog() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := helpers.VerifyUserType(c, helpers.AdminRole); err != nil {
+			helpers.HandleError(c, err)
+			return
+		}
+
+		page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+		if err != nil || page < 1 {
+			helpers.HandleError(c, apperrors.Validation("invalid page number"))
+			return
+		}
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+		if err != nil || limit < 1 {
+			helpers.HandleError(c, apperrors.Validation("invalid limit number"))
+			return
+		}
+		skip := (page - 1) * limit
+
+		filter := bson.M{}
+		for _, key := range []string{"actor_id", "resource", "resource_id"} {
+			if value := c.Query(key); value != "" {
+				filter[key] = value
+			}
+		}
+
+		createdAt := bson.M{}
+		for param, operator := range map[string]string{"from": "$gte", "to": "$lt"} {
+			raw := c.Query(param)
+			if raw == "" {
+				continue
+			}
+			t, err := time.Parse(time.RFC3339, raw)
+			if err != nil {
+				helpers.HandleError(c, apperrors.Validation(fmt.Sprintf("%s must be an RFC 3339 time", param)))
+				return
+			}
+			createdAt[operator] = t
+		}
+		if len(createdAt) > 0 {
+			filter["created_at"] = createdAt
+		}
+
+		ctx, cancel := helpers.RequestContext(c)
+		defer cancel()
+
+		findOptions := options.Find()
+		findOptions.SetSort(bson.D{{Key: "created_at", Value: -1}})
+		findOptions.SetSkip(int64(skip))
+		findOptions.SetLimit(int64(limit))
+
+		cursor, err := ac.auditCollection.Find(ctx, filter, findOptions)
+		if err != nil {
+			helpers.HandleError(c, apperrors.Internal(fmt.Errorf("finding audit entries: %w", err)))
+			return
+		}
+		defer cursor.Close(ctx)
+
+		var entries []models.AuditEntry
+		if err := cursor.All(ctx, &entries); err != nil {
+			helpers.HandleError(c, apperrors.Internal(fmt.Errorf("decoding audit entries: %w", err)))
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"entries": dto.NewAuditEntryResponses(entries)})
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
 	"net/http"
 	"strconv"
 	"time"
@@ -12,7 +11,9 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 	"github.com/mayurvarma14/go-movie-review/database"
+	"github.com/mayurvarma14/go-movie-review/dto"
 	"github.com/mayurvarma14/go-movie-review/helpers"
+	"github.com/mayurvarma14/go-movie-review/internals/apperrors"
 	"github.com/mayurvarma14/go-movie-review/models"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
@@ -21,59 +22,63 @@ import (
 
 type GenreController struct {
 	genreCollection *mongo.Collection
+	auditCollection *mongo.Collection
 	validate        *validator.Validate
 }
 
 func NewGenreController(db *database.Database) *GenreController {
 	return &GenreController{
 		genreCollection: db.Client.Database(db.Name).Collection("genre"),
-		validate:        validator.New(),
+		auditCollection: db.Client.Database(db.Name).Collection("audit"),
+		validate:        helpers.NewValidator(),
 	}
 }
 
 func (gc *GenreController) CreateGenre() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if err := helpers.VerifyUserType(c, helpers.AdminRole); err != nil {
-			helpers.HandleError(c, http.StatusForbidden, err)
+			helpers.HandleError(c, err)
 			return
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		ctx, cancel := helpers.RequestContext(c)
 		defer cancel()
 
-		var genre models.Genre
-		if err := c.BindJSON(&genre); err != nil {
-			helpers.HandleError(c, http.StatusBadRequest, fmt.Errorf("binding JSON: %w", err))
+		var req dto.GenreRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			helpers.HandleError(c, apperrors.InvalidInput(err))
 			return
 		}
 
-		if err := gc.validate.Struct(&genre); err != nil {
-			helpers.HandleError(c, http.StatusBadRequest, fmt.Errorf("validation: %w", err))
+		if err := gc.validate.Struct(&req); err != nil {
+			helpers.HandleError(c, apperrors.InvalidInput(err))
 			return
 		}
 
-		count, err := gc.genreCollection.CountDocuments(ctx, bson.M{"name": bson.M{"$regex": *genre.Name, "$options": "i"}})
+		count, err := gc.genreCollection.CountDocuments(ctx, helpers.NotDeleted(bson.M{"name": bson.M{"$regex": req.Name, "$options": "i"}}))
 		if err != nil {
-			log.Printf("Error checking genre: %v", err)
-			helpers.HandleError(c, http.StatusInternalServerError, fmt.Errorf("checking genre: %w", err))
+			helpers.HandleError(c, apperrors.Internal(fmt.Errorf("checking genre: %w", err)))
 			return
 		}
 		if count > 0 {
-			helpers.HandleError(c, http.StatusBadRequest, errors.New("genre already exists"))
+			helpers.HandleError(c, apperrors.Conflict("genre already exists"))
 			return
 		}
 
+		genre := req.Model()
 		genre.ID = bson.NewObjectID()
 		genre.CreatedAt = time.Now()
 		genre.UpdatedAt = time.Now()
+		genre.Version = 1
 
-		result, err := gc.genreCollection.InsertOne(ctx, genre)
-		if err != nil {
-			helpers.HandleError(c, http.StatusInternalServerError, fmt.Errorf("inserting genre: %w", err))
+		if _, err := gc.genreCollection.InsertOne(ctx, genre); err != nil {
+			helpers.HandleError(c, apperrors.Internal(fmt.Errorf("inserting genre: %w", err)))
 			return
 		}
+		helpers.RecordAudit(ctx, c, gc.auditCollection, helpers.AuditCreate, "genre", strconv.Itoa(genre.GenreID), nil, genre)
 
-		c.JSON(http.StatusCreated, gin.H{"message": "Genre created successfully", "genre_id": result.InsertedID})
+		c.Header("ETag", helpers.ETag(genre.Version))
+		c.JSON(http.StatusCreated, gin.H{"message": "Genre created successfully", "genre_id": genre.GenreID})
 	}
 }
 
@@ -82,41 +87,44 @@ func (gc *GenreController) GetGenre() gin.HandlerFunc {
 		genreIDStr := c.Param("genre_id")
 		genreID, err := strconv.Atoi(genreIDStr)
 		if err != nil {
-			helpers.HandleError(c, http.StatusBadRequest, fmt.Errorf("invalid genre ID: %w", err))
+			helpers.HandleError(c, apperrors.Validation("invalid genre ID"))
 			return
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		ctx, cancel := helpers.RequestContext(c)
 		defer cancel()
 
 		var genre models.Genre
-		err = gc.genreCollection.FindOne(ctx, bson.M{"genre_id": genreID}).Decode(&genre)
+		err = gc.genreCollection.FindOne(ctx, helpers.NotDeleted(bson.M{"genre_id": genreID})).Decode(&genre)
 		if err != nil {
 			if errors.Is(err, mongo.ErrNoDocuments) {
-				helpers.HandleError(c, http.StatusNotFound, errors.New("genre not found"))
+				helpers.HandleError(c, apperrors.NotFound("genre not found"))
 			} else {
-				helpers.HandleError(c, http.StatusInternalServerError, fmt.Errorf("finding genre: %w", err))
+				helpers.HandleError(c, apperrors.Internal(fmt.Errorf("finding genre: %w", err)))
 			}
 			return
 		}
 
-		c.JSON(http.StatusOK, genre)
+		if helpers.ConditionalGet(c, genre.Version) {
+			return
+		}
+		c.JSON(http.StatusOK, dto.NewGenreResponse(&genre))
 	}
 }
 
 func (gc *GenreController) GetGenres() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		ctx, cancel := helpers.RequestContext(c)
 		defer cancel()
 
 		page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
 		if err != nil || page < 1 {
-			helpers.HandleError(c, http.StatusBadRequest, errors.New("invalid page number"))
+			helpers.HandleError(c, apperrors.Validation("invalid page number"))
 			return
 		}
 		limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
 		if err != nil || limit < 1 {
-			helpers.HandleError(c, http.StatusBadRequest, errors.New("invalid limit number"))
+			helpers.HandleError(c, apperrors.Validation("invalid limit number"))
 			return
 		}
 		skip := (page - 1) * limit
@@ -125,101 +133,463 @@ func (gc *GenreController) GetGenres() gin.HandlerFunc {
 		findOptions.SetSkip(int64(skip))
 		findOptions.SetLimit(int64(limit))
 
-		cursor, err := gc.genreCollection.Find(ctx, bson.M{}, findOptions)
+		cursor, err := gc.genreCollection.Find(ctx, helpers.NotDeleted(bson.M{}), findOptions)
 		if err != nil {
-			helpers.HandleError(c, http.StatusInternalServerError, fmt.Errorf("finding genres: %w", err))
+			helpers.HandleError(c, apperrors.Internal(fmt.Errorf("finding genres: %w", err)))
 			return
 		}
 		defer cursor.Close(ctx)
 
 		var genres []models.Genre
 		if err := cursor.All(ctx, &genres); err != nil {
-			helpers.HandleError(c, http.StatusInternalServerError, fmt.Errorf("decoding genres: %w", err))
+			helpers.HandleError(c, apperrors.Internal(fmt.Errorf("decoding genres: %w", err)))
 			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{"genres": genres})
+		c.JSON(http.StatusOK, gin.H{"genres": dto.NewGenreResponses(genres)})
 	}
 }
 
 func (gc *GenreController) EditGenre() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if err := helpers.VerifyUserType(c, helpers.AdminRole); err != nil {
-			helpers.HandleError(c, http.StatusForbidden, err)
+			helpers.HandleError(c, err)
 			return
 		}
 
 		genreIDStr := c.Param("genre_id")
 		genreID, err := strconv.Atoi(genreIDStr)
 		if err != nil {
-			helpers.HandleError(c, http.StatusBadRequest, fmt.Errorf("invalid genre ID: %w", err))
+			helpers.HandleError(c, apperrors.Validation("invalid genre ID"))
 			return
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		ctx, cancel := helpers.RequestContext(c)
 		defer cancel()
 
-		var updatedGenre models.Genre
-		if err := c.BindJSON(&updatedGenre); err != nil {
-			helpers.HandleError(c, http.StatusBadRequest, fmt.Errorf("binding JSON: %w", err))
+		var req dto.GenreRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			helpers.HandleError(c, apperrors.InvalidInput(err))
 			return
 		}
 
-		if err := gc.validate.Struct(&updatedGenre); err != nil {
-			helpers.HandleError(c, http.StatusBadRequest, fmt.Errorf("validation: %w", err))
+		if err := gc.validate.Struct(&req); err != nil {
+			helpers.HandleError(c, apperrors.InvalidInput(err))
 			return
 		}
 
-		update := bson.M{
-			"$set": bson.M{
-				"name":       updatedGenre.Name,
-				"updated_at": time.Now(),
-			},
-		}
+		versions := helpers.IfMatch(c)
+		filter := helpers.MatchVersions(helpers.NotDeleted(bson.M{"genre_id": genreID}), versions)
+		update := helpers.VersionedUpdate(genreFields(&req))
+		opts := options.FindOneAndUpdate().SetReturnDocument(options.Before)
 
-		result, err := gc.genreCollection.UpdateOne(ctx, bson.M{"genre_id": genreID}, update)
+		var before models.Genre
+		err = gc.genreCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&before)
 		if err != nil {
-			helpers.HandleError(c, http.StatusInternalServerError, fmt.Errorf("updating genre: %w", err))
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				helpers.HandleError(c, helpers.MissedWrite(ctx, gc.genreCollection, filter, versions, "genre not found"))
+			} else {
+				helpers.HandleError(c, apperrors.Internal(fmt.Errorf("updating genre: %w", err)))
+			}
 			return
 		}
 
-		if result.MatchedCount == 0 {
-			helpers.HandleError(c, http.StatusNotFound, errors.New("genre not found"))
+		genre, err := helpers.Updated(&before, update)
+		if err != nil {
+			helpers.HandleError(c, apperrors.Internal(err))
 			return
 		}
+		helpers.RecordAudit(ctx, c, gc.auditCollection, helpers.AuditUpdate, "genre", strconv.Itoa(genreID), before, genre)
 
+		c.Header("ETag", helpers.ETag(genre.Version))
 		c.JSON(http.StatusOK, gin.H{"message": "Genre updated successfully"})
 	}
 }
 
+// PatchGenre applies a JSON merge patch to a genre and answers with the
+// result. The merged genre must pass the same validation as a PUT body.
+func (gc *GenreController) PatchGenre() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := helpers.VerifyUserType(c, helpers.AdminRole); err != nil {
+			helpers.HandleError(c, err)
+			return
+		}
+
+		genreID, err := strconv.Atoi(c.Param("genre_id"))
+		if err != nil {
+			helpers.HandleError(c, apperrors.Validation("invalid genre ID"))
+			return
+		}
+
+		ctx, cancel := helpers.RequestContext(c)
+		defer cancel()
+
+		var genre models.Genre
+		err = gc.genreCollection.FindOne(ctx, helpers.NotDeleted(bson.M{"genre_id": genreID})).Decode(&genre)
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				helpers.HandleError(c, apperrors.NotFound("genre not found"))
+			} else {
+				helpers.HandleError(c, apperrors.Internal(fmt.Errorf("finding genre: %w", err)))
+			}
+			return
+		}
+
+		versions := helpers.IfMatch(c)
+		if !helpers.VersionMatches(genre.Version, versions) {
+			helpers.HandleError(c, apperrors.PreconditionFailed("resource was modified since it was fetched"))
+			return
+		}
+
+		req, err := helpers.BindMergePatch(c, dto.NewGenreRequest(&genre))
+		if err != nil {
+			helpers.HandleError(c, err)
+			return
+		}
+		if err := gc.validate.Struct(&req); err != nil {
+			helpers.HandleError(c, apperrors.InvalidInput(err))
+			return
+		}
+		if req.GenreID != genreID {
+			helpers.HandleError(c, apperrors.Validation("genre_id cannot be changed"))
+			return
+		}
+
+		// Pin the write to the version the patch was applied to, so a
+		// concurrent edit in between fails instead of being overwritten.
+		pinned := []int64{genre.Version}
+		filter := helpers.MatchVersions(helpers.NotDeleted(bson.M{"genre_id": genreID}), pinned)
+		opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+		var patched models.Genre
+		err = gc.genreCollection.FindOneAndUpdate(ctx, filter, helpers.VersionedUpdate(genreFields(&req)), opts).Decode(&patched)
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				helpers.HandleError(c, helpers.MissedWrite(ctx, gc.genreCollection, filter, pinned, "genre not found"))
+			} else {
+				helpers.HandleError(c, apperrors.Internal(fmt.Errorf("updating genre: %w", err)))
+			}
+			return
+		}
+		helpers.RecordAudit(ctx, c, gc.auditCollection, helpers.AuditUpdate, "genre", strconv.Itoa(genreID), genre, patched)
+
+		c.Header("ETag", helpers.ETag(patched.Version))
+		c.JSON(http.StatusOK, dto.NewGenreResponse(&patched))
+	}
+}
+
+// genreFields lists what PUT and PATCH may change; genre_id stays fixed.
+func genreFields(req *dto.GenreRequest) bson.M {
+	return bson.M{
+		"name":       req.Name,
+		"updated_at": time.Now(),
+	}
+}
+
 func (gc *GenreController) DeleteGenre() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if err := helpers.VerifyUserType(c, helpers.AdminRole); err != nil {
-			helpers.HandleError(c, http.StatusForbidden, err)
+			helpers.HandleError(c, err)
 			return
 		}
 
 		genreIDStr := c.Param("genre_id")
 		genreID, err := strconv.Atoi(genreIDStr)
 		if err != nil {
-			helpers.HandleError(c, http.StatusBadRequest, fmt.Errorf("invalid genre ID: %w", err))
+			helpers.HandleError(c, apperrors.Validation("invalid genre ID"))
 			return
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		ctx, cancel := helpers.RequestContext(c)
 		defer cancel()
 
-		result, err := gc.genreCollection.DeleteOne(ctx, bson.M{"genre_id": genreID})
+		versions := helpers.IfMatch(c)
+		filter := helpers.MatchVersions(helpers.NotDeleted(bson.M{"genre_id": genreID}), versions)
+		update := helpers.SoftDelete(c.GetString("uid"))
+		opts := options.FindOneAndUpdate().SetReturnDocument(options.Before)
+
+		var before models.Genre
+		err = gc.genreCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&before)
 		if err != nil {
-			helpers.HandleError(c, http.StatusInternalServerError, fmt.Errorf("deleting genre: %w", err))
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				helpers.HandleError(c, helpers.MissedWrite(ctx, gc.genreCollection, filter, versions, "genre not found"))
+			} else {
+				helpers.HandleError(c, apperrors.Internal(fmt.Errorf("deleting genre: %w", err)))
+			}
 			return
 		}
 
-		if result.DeletedCount == 0 {
-			helpers.HandleError(c, http.StatusNotFound, errors.New("genre not found"))
+		after, err := helpers.Updated(&before, update)
+		if err != nil {
+			helpers.HandleError(c, apperrors.Internal(err))
 			return
 		}
+		helpers.RecordAudit(ctx, c, gc.auditCollection, helpers.AuditDelete, "genre", strconv.Itoa(genreID), before, after)
 
 		c.JSON(http.StatusOK, gin.H{"message": "Genre deleted successfully"})
 	}
 }
+
+// GetDeletedGenres lists genres in the trash, most recently deleted first.
+func (gc *GenreController) GetDeletedGenres() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := helpers.VerifyUserType(c, helpers.AdminRole); err != nil {
+			helpers.HandleError(c, err)
+			return
+		}
+
+		ctx, cancel := helpers.RequestContext(c)
+		defer cancel()
+
+		page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+		if err != nil || page < 1 {
+			helpers.HandleError(c, apperrors.Validation("invalid page number"))
+			return
+		}
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+		if err != nil || limit < 1 {
+			helpers.HandleError(c, apperrors.Validation("invalid limit number"))
+			return
+		}
+		skip := (page - 1) * limit
+
+		findOptions := options.Find()
+		findOptions.SetSort(bson.D{{Key: "deleted_at", Value: -1}})
+		findOptions.SetSkip(int64(skip))
+		findOptions.SetLimit(int64(limit))
+
+		cursor, err := gc.genreCollection.Find(ctx, helpers.Trashed(bson.M{}), findOptions)
+		if err != nil {
+			helpers.HandleError(c, apperrors.Internal(fmt.Errorf("finding deleted genres: %w", err)))
+			return
+		}
+		defer cursor.Close(ctx)
+
+		var genres []models.Genre
+		if err := cursor.All(ctx, &genres); err != nil {
+			helpers.HandleError(c, apperrors.Internal(fmt.Errorf("decoding genres: %w", err)))
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"genres": dto.NewGenreResponses(genres)})
+	}
+}
+
+// RestoreGenre takes a genre out of the trash. It refuses when another genre
+// has taken over the genre_id in the meantime.
+func (gc *GenreController) RestoreGenre() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := helpers.VerifyUserType(c, helpers.AdminRole); err != nil {
+			helpers.HandleError(c, err)
+			return
+		}
+
+		genreID, err := strconv.Atoi(c.Param("genre_id"))
+		if err != nil {
+			helpers.HandleError(c, apperrors.Validation("invalid genre ID"))
+			return
+		}
+
+		ctx, cancel := helpers.RequestContext(c)
+		defer cancel()
+
+		count, err := gc.genreCollection.CountDocuments(ctx, helpers.NotDeleted(bson.M{"genre_id": genreID}))
+		if err != nil {
+			helpers.HandleError(c, apperrors.Internal(fmt.Errorf("checking genre: %w", err)))
+			return
+		}
+		if count > 0 {
+			helpers.HandleError(c, apperrors.Conflict("another genre with this ID exists"))
+			return
+		}
+
+		versions := helpers.IfMatch(c)
+		filter := helpers.MatchVersions(helpers.Trashed(bson.M{"genre_id": genreID}), versions)
+		update := helpers.Restore()
+		opts := options.FindOneAndUpdate().SetReturnDocument(options.Before)
+
+		var before models.Genre
+		err = gc.genreCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&before)
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				helpers.HandleError(c, helpers.MissedWrite(ctx, gc.genreCollection, filter, versions, "genre not found in trash"))
+			} else {
+				helpers.HandleError(c, apperrors.Internal(fmt.Errorf("restoring genre: %w", err)))
+			}
+			return
+		}
+
+		genre, err := helpers.Updated(&before, update)
+		if err != nil {
+			helpers.HandleError(c, apperrors.Internal(err))
+			return
+		}
+		helpers.RecordAudit(ctx, c, gc.auditCollection, helpers.AuditRestore, "genre", strconv.Itoa(genreID), before, genre)
+
+		c.Header("ETag", helpers.ETag(genre.Version))
+		c.JSON(http.StatusOK, dto.NewGenreResponse(genre))
+	}
+}
+
+// ImportGenres creates and updates genres from a CSV or NDJSON upload, keyed
+// by genre_id. Rows that match the stored genre are skipped, a row that
+// fails never stops the others, and dry_run=true reports what would happen
+// without writing anything.
+func (gc *GenreController) ImportGenres() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := helpers.VerifyUserType(c, helpers.AdminRole); err != nil {
+			helpers.HandleError(c, err)
+			return
+		}
+
+		dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+		if err != nil {
+			helpers.HandleError(c, apperrors.Validation("dry_run must be true or false"))
+			return
+		}
+
+		rows, err := helpers.ReadImport(c, dto.GenreImportColumns, dto.NewGenreImportRow)
+		if err != nil {
+			helpers.HandleError(c, err)
+			return
+		}
+
+		ctx, cancel := helpers.RequestContext(c)
+		defer cancel()
+
+		report, err := gc.ImportRows(ctx, rows, dryRun, helpers.RequestActor(c))
+		if err != nil {
+			helpers.HandleError(c, apperrors.Internal(err))
+			return
+		}
+
+		c.JSON(http.StatusOK, report)
+	}
+}
+
+// ImportRows does the work of ImportGenres on rows that are already decoded,
+// which lets the admin CLI import files too. Writes are audited as actor's.
+func (gc *GenreController) ImportRows(ctx context.Context, rows []helpers.ImportRow[dto.GenreRequest], dryRun bool, actor helpers.AuditActor) (dto.ImportReport, error) {
+	results := make([]dto.ImportRowResult, len(rows))
+	seen := make(map[int]bool, len(rows))
+	genreIDs := []int{}
+	for i := range rows {
+		row := &rows[i]
+		results[i] = dto.ImportRowResult{Line: row.Line, Status: dto.ImportFailed, GenreID: row.Value.GenreID}
+		if row.Err == nil {
+			row.Err = gc.validate.Struct(&row.Value)
+		}
+		if row.Err == nil && seen[row.Value.GenreID] {
+			row.Err = errors.New("genre_id appears on an earlier line")
+		}
+		if row.Err != nil {
+			results[i].Error = helpers.ImportError(row.Err)
+			continue
+		}
+
+		seen[row.Value.GenreID] = true
+		genreIDs = append(genreIDs, row.Value.GenreID)
+	}
+
+	cursor, err := gc.genreCollection.Find(ctx, helpers.NotDeleted(bson.M{"genre_id": bson.M{"$in": genreIDs}}))
+	if err != nil {
+		return dto.ImportReport{}, fmt.Errorf("finding genres: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var stored []models.Genre
+	if err := cursor.All(ctx, &stored); err != nil {
+		return dto.ImportReport{}, fmt.Errorf("decoding genres: %w", err)
+	}
+	existing := make(map[int]models.Genre, len(stored))
+	for _, genre := range stored {
+		existing[genre.GenreID] = genre
+	}
+
+	// writes, their rows and their audit entries share indexes, which is
+	// how bulk write errors point back at rows.
+	var writes []mongo.WriteModel
+	var writeRows []int
+	var changes []helpers.AuditChange
+	for i, row := range rows {
+		if row.Err != nil {
+			continue
+		}
+
+		req := row.Value
+		before, found := existing[req.GenreID]
+		switch {
+		case !found:
+			genre := req.Model()
+			genre.ID = bson.NewObjectID()
+			genre.CreatedAt = time.Now()
+			genre.UpdatedAt = genre.CreatedAt
+			genre.Version = 1
+			writes = append(writes, mongo.NewInsertOneModel().SetDocument(genre))
+			changes = append(changes, helpers.AuditChange{Action: helpers.AuditCreate, ResourceID: strconv.Itoa(req.GenreID), After: genre})
+			results[i].Status = dto.ImportCreated
+		case dto.NewGenreRequest(&before) == req:
+			results[i].Status = dto.ImportSkipped
+			continue
+		default:
+			update := helpers.VersionedUpdate(genreFields(&req))
+			after, err := helpers.Updated(&before, update)
+			if err != nil {
+				return dto.ImportReport{}, err
+			}
+			writes = append(writes, mongo.NewUpdateOneModel().SetFilter(helpers.NotDeleted(bson.M{"genre_id": req.GenreID})).SetUpdate(update))
+			changes = append(changes, helpers.AuditChange{Action: helpers.AuditUpdate, ResourceID: strconv.Itoa(req.GenreID), Before: before, After: after})
+			results[i].Status = dto.ImportUpdated
+		}
+		writeRows = append(writeRows, i)
+	}
+
+	if !dryRun && len(writes) > 0 {
+		failed, err := helpers.BulkWrite(ctx, gc.genreCollection, writes)
+		if err != nil {
+			return dto.ImportReport{}, fmt.Errorf("importing genres: %w", err)
+		}
+
+		var written []helpers.AuditChange
+		for w, i := range writeRows {
+			if err, ok := failed[w]; ok {
+				results[i].Status = dto.ImportFailed
+				results[i].Error = helpers.ImportWriteError(err, "genre_id")
+				continue
+			}
+			written = append(written, changes[w])
+		}
+		helpers.RecordAuditsAs(ctx, actor, gc.auditCollection, "genre", written)
+	}
+
+	return dto.NewImportReport(dryRun, results), nil
+}
+
+// ExportGenres streams every genre as CSV, JSON or NDJSON.
+func (gc *GenreController) ExportGenres() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := helpers.VerifyUserType(c, helpers.AdminRole); err != nil {
+			helpers.HandleError(c, err)
+			return
+		}
+
+		format, err := helpers.ExportFormat(c)
+		if err != nil {
+			helpers.HandleError(c, err)
+			return
+		}
+
+		ctx, cancel := helpers.RequestContext(c)
+		defer cancel()
+
+		findOptions := options.Find().SetSort(bson.D{{Key: "genre_id", Value: 1}})
+		cursor, err := gc.genreCollection.Find(ctx, helpers.NotDeleted(bson.M{}), findOptions)
+		if err != nil {
+			helpers.HandleError(c, apperrors.Internal(fmt.Errorf("finding genres: %w", err)))
+			return
+		}
+		defer cursor.Close(ctx)
+
+		helpers.StreamExport(ctx, c, cursor, format, "genres", dto.GenreExportColumns, dto.NewGenreResponse)
+	}
+}
@@ -0,0 +1,452 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mayurvarma14/go-movie-review/dto"
+	"github.com/mayurvarma14/go-movie-review/helpers"
+	"github.com/mayurvarma14/go-movie-review/internals/apperrors"
+	"github.com/mayurvarma14/go-movie-review/internals/metrics"
+	"github.com/mayurvarma14/go-movie-review/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// maxMFAAttempts is how many codes one mfa_token may try before the user has
+// to sign in again.
+const maxMFAAttempts = 5
+
+func (uc *UserController) VerifyMFALogin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := helpers.RequestContext(c)
+		defer cancel()
+
+		var req dto.MFALoginRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			helpers.HandleError(c, apperrors.InvalidInput(err))
+			return
+		}
+
+		if err := uc.validate.Struct(&req); err != nil {
+			helpers.HandleError(c, apperrors.InvalidInput(err))
+			return
+		}
+
+		claims, err := uc.tokens.ValidateMFAToken(req.MFAToken)
+		if err != nil {
+			helpers.HandleError(c, apperrors.Unauthorized("invalid or expired MFA token").Wrap(err))
+			return
+		}
+
+		var user models.User
+		err = uc.userCollection.FindOne(ctx, bson.M{"user_id": claims.Uid}).Decode(&user)
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				helpers.HandleError(c, apperrors.Unauthorized("invalid MFA token"))
+			} else {
+				helpers.HandleError(c, apperrors.Internal(fmt.Errorf("finding user: %w", err)))
+			}
+			return
+		}
+
+		if !user.MFAEnabled || user.MFASecret == nil {
+			helpers.HandleError(c, apperrors.Validation("MFA is not enabled for this user"))
+			return
+		}
+
+		allowed, err := uc.startMFAAttempt(ctx, claims)
+		if err != nil {
+			helpers.HandleError(c, apperrors.Internal(err))
+			return
+		}
+		if !allowed {
+			metrics.LoginAttempts.WithLabelValues("mfa_failure").Inc()
+			helpers.HandleError(c, apperrors.Unauthorized("MFA challenge is no longer valid, sign in again"))
+			return
+		}
+
+		if req.Code != "" {
+			accepted, err := uc.acceptTOTP(ctx, &user, req.Code)
+			if err != nil {
+				helpers.HandleError(c, apperrors.Internal(err))
+				return
+			}
+			if !accepted {
+				metrics.LoginAttempts.WithLabelValues("mfa_failure").Inc()
+				helpers.HandleError(c, apperrors.Unauthorized("invalid MFA code"))
+				return
+			}
+		} else {
+			used, err := uc.consumeRecoveryCode(ctx, user.UserID, req.RecoveryCode)
+			if err != nil {
+				helpers.HandleError(c, apperrors.Internal(err))
+				return
+			}
+			if !used {
+				metrics.LoginAttempts.WithLabelValues("mfa_failure").Inc()
+				helpers.HandleError(c, apperrors.Unauthorized("invalid recovery code"))
+				return
+			}
+		}
+
+		if err := uc.closeMFAChallenge(ctx, claims.Id); err != nil {
+			helpers.HandleError(c, apperrors.Internal(err))
+			return
+		}
+
+		uc.issueTokens(ctx, c, &user, false, req.DeviceLabel)
+		if !c.IsAborted() {
+			metrics.LoginAttempts.WithLabelValues("success").Inc()
+		}
+	}
+}
+
+func (uc *UserController) EnrollMFA() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := helpers.RequestContext(c)
+		defer cancel()
+
+		user, ok := uc.currentUser(ctx, c)
+		if !ok {
+			return
+		}
+
+		if user.MFAEnabled {
+			helpers.HandleError(c, apperrors.Validation("MFA is already enabled"))
+			return
+		}
+
+		secret, err := helpers.GenerateTOTPSecret()
+		if err != nil {
+			helpers.HandleError(c, apperrors.Internal(err))
+			return
+		}
+
+		update := bson.M{"$set": bson.M{"mfa_pending_secret": secret, "updated_at": time.Now()}}
+		if _, err := uc.userCollection.UpdateOne(ctx, bson.M{"user_id": user.UserID}, update); err != nil {
+			helpers.HandleError(c, apperrors.Internal(fmt.Errorf("storing MFA secret: %w", err)))
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"message":     "Scan the otpauth URI with an authenticator app and confirm with a code",
+			"secret":      secret,
+			"otpauth_uri": helpers.TOTPURI(*user.Email, secret),
+		})
+	}
+}
+
+func (uc *UserController) ConfirmMFA() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := helpers.RequestContext(c)
+		defer cancel()
+
+		var req dto.MFACodeRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			helpers.HandleError(c, apperrors.InvalidInput(err))
+			return
+		}
+
+		if err := uc.validate.Struct(&req); err != nil {
+			helpers.HandleError(c, apperrors.InvalidInput(err))
+			return
+		}
+
+		user, ok := uc.currentUser(ctx, c)
+		if !ok {
+			return
+		}
+
+		if user.MFAPendingSecret == nil {
+			helpers.HandleError(c, apperrors.Validation("no MFA enrollment in progress"))
+			return
+		}
+
+		step, ok := helpers.ValidateTOTP(*user.MFAPendingSecret, req.Code, 0)
+		if !ok {
+			helpers.HandleError(c, apperrors.Validation("invalid MFA code"))
+			return
+		}
+
+		codes, hashes, err := helpers.GenerateRecoveryCodes()
+		if err != nil {
+			helpers.HandleError(c, apperrors.Internal(err))
+			return
+		}
+
+		update := bson.M{
+			"$set": bson.M{
+				"mfa_enabled":    true,
+				"mfa_secret":     *user.MFAPendingSecret,
+				"mfa_last_step":  step,
+				"recovery_codes": hashes,
+				"updated_at":     time.Now(),
+			},
+			"$unset": bson.M{"mfa_pending_secret": ""},
+		}
+		if _, err := uc.userCollection.UpdateOne(ctx, bson.M{"user_id": user.UserID}, update); err != nil {
+			helpers.HandleError(c, apperrors.Internal(fmt.Errorf("enabling MFA: %w", err)))
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "MFA enabled successfully", "recovery_codes": codes})
+	}
+}
+
+func (uc *UserController) DisableMFA() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := helpers.RequestContext(c)
+		defer cancel()
+
+		var req dto.MFACodeRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			helpers.HandleError(c, apperrors.InvalidInput(err))
+			return
+		}
+
+		if err := uc.validate.Struct(&req); err != nil {
+			helpers.HandleError(c, apperrors.InvalidInput(err))
+			return
+		}
+
+		user, ok := uc.currentUser(ctx, c)
+		if !ok {
+			return
+		}
+
+		if !user.MFAEnabled || user.MFASecret == nil {
+			helpers.HandleError(c, apperrors.Validation("MFA is not enabled"))
+			return
+		}
+
+		if *user.UserType == helpers.AdminRole {
+			settings, err := uc.securitySettings(ctx)
+			if err != nil {
+				helpers.HandleError(c, apperrors.Internal(err))
+				return
+			}
+			if settings.RequireAdminMFA {
+				helpers.HandleError(c, apperrors.Forbidden("MFA is required for ADMIN accounts"))
+				return
+			}
+		}
+
+		accepted, err := uc.acceptTOTP(ctx, user, req.Code)
+		if err != nil {
+			helpers.HandleError(c, apperrors.Internal(err))
+			return
+		}
+		if !accepted {
+			helpers.HandleError(c, apperrors.Validation("invalid MFA code"))
+			return
+		}
+
+		update := bson.M{
+			"$set":   bson.M{"mfa_enabled": false, "updated_at": time.Now()},
+			"$unset": bson.M{"mfa_secret": "", "mfa_last_step": "", "mfa_pending_secret": "", "recovery_codes": ""},
+		}
+		if _, err := uc.userCollection.UpdateOne(ctx, bson.M{"user_id": user.UserID}, update); err != nil {
+			helpers.HandleError(c, apperrors.Internal(fmt.Errorf("disabling MFA: %w", err)))
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "MFA disabled successfully"})
+	}
+}
+
+func (uc *UserController) RegenerateRecoveryCodes() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := helpers.RequestContext(c)
+		defer cancel()
+
+		var req dto.MFACodeRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			helpers.HandleError(c, apperrors.InvalidInput(err))
+			return
+		}
+
+		if err := uc.validate.Struct(&req); err != nil {
+			helpers.HandleError(c, apperrors.InvalidInput(err))
+			return
+		}
+
+		user, ok := uc.currentUser(ctx, c)
+		if !ok {
+			return
+		}
+
+		if !user.MFAEnabled || user.MFASecret == nil {
+			helpers.HandleError(c, apperrors.Validation("MFA is not enabled"))
+			return
+		}
+
+		accepted, err := uc.acceptTOTP(ctx, user, req.Code)
+		if err != nil {
+			helpers.HandleError(c, apperrors.Internal(err))
+			return
+		}
+		if !accepted {
+			helpers.HandleError(c, apperrors.Validation("invalid MFA code"))
+			return
+		}
+
+		codes, hashes, err := helpers.GenerateRecoveryCodes()
+		if err != nil {
+			helpers.HandleError(c, apperrors.Internal(err))
+			return
+		}
+
+		update := bson.M{"$set": bson.M{"recovery_codes": hashes, "updated_at": time.Now()}}
+		if _, err := uc.userCollection.UpdateOne(ctx, bson.M{"user_id": user.UserID}, update); err != nil {
+			helpers.HandleError(c, apperrors.Internal(fmt.Errorf("storing recovery codes: %w", err)))
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Recovery codes regenerated", "recovery_codes": codes})
+	}
+}
+
+func (uc *UserController) SetMFAPolicy() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := helpers.VerifyUserType(c, helpers.AdminRole); err != nil {
+			helpers.HandleError(c, err)
+			return
+		}
+
+		ctx, cancel := helpers.RequestContext(c)
+		defer cancel()
+
+		var req dto.MFAPolicyRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			helpers.HandleError(c, apperrors.InvalidInput(err))
+			return
+		}
+
+		if err := uc.validate.Struct(&req); err != nil {
+			helpers.HandleError(c, apperrors.InvalidInput(err))
+			return
+		}
+
+		settings := models.SecuritySettings{
+			ID:              models.SecuritySettingsID,
+			RequireAdminMFA: *req.RequireAdminMFA,
+			UpdatedAt:       time.Now(),
+		}
+
+		// The settings document may not exist yet, in which case the audit
+		// entry has no before snapshot.
+		var before any
+		var previous models.SecuritySettings
+		opts := options.FindOneAndReplace().SetUpsert(true).SetReturnDocument(options.Before)
+		err := uc.settingsCollection.FindOneAndReplace(ctx, bson.M{"_id": settings.ID}, settings, opts).Decode(&previous)
+		switch {
+		case err == nil:
+			before = previous
+		case !errors.Is(err, mongo.ErrNoDocuments):
+			helpers.HandleError(c, apperrors.Internal(fmt.Errorf("updating MFA policy: %w", err)))
+			return
+		}
+		helpers.RecordAudit(ctx, c, uc.auditCollection, helpers.AuditUpdate, "settings", settings.ID, before, settings)
+
+		c.JSON(http.StatusOK, dto.NewSecuritySettingsResponse(&settings))
+	}
+}
+
+func (uc *UserController) currentUser(ctx context.Context, c *gin.Context) (*models.User, bool) {
+	var user models.User
+	err := uc.userCollection.FindOne(ctx, bson.M{"user_id": c.GetString("uid")}).Decode(&user)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			helpers.HandleError(c, apperrors.NotFound("user not found"))
+		} else {
+			helpers.HandleError(c, apperrors.Internal(fmt.Errorf("finding user: %w", err)))
+		}
+		return nil, false
+	}
+	return &user, true
+}
+
+// acceptTOTP checks code against the user's secret and records the time step
+// it matched. The conditional write refuses a step that was already used, so
+// two requests racing with the same code cannot both succeed.
+func (uc *UserController) acceptTOTP(ctx context.Context, user *models.User, code string) (bool, error) {
+	step, ok := helpers.ValidateTOTP(*user.MFASecret, code, user.MFALastStep)
+	if !ok {
+		return false, nil
+	}
+	result, err := uc.userCollection.UpdateOne(ctx,
+		bson.M{"user_id": user.UserID, "mfa_last_step": bson.M{"$not": bson.M{"$gte": step}}},
+		bson.M{"$set": bson.M{"mfa_last_step": step}},
+	)
+	if err != nil {
+		return false, fmt.Errorf("recording MFA code: %w", err)
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// startMFAAttempt counts an attempt against the challenge behind an mfa_token
+// before its code is checked, so parallel guesses cannot get past the cap. It
+// reports false once the challenge has used up its attempts or been answered.
+func (uc *UserController) startMFAAttempt(ctx context.Context, claims *helpers.JwtSignedDetails) (bool, error) {
+	update := bson.M{
+		"$inc":         bson.M{"attempts": 1},
+		"$setOnInsert": bson.M{"expires_at": time.Unix(claims.ExpiresAt, 0)},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var challenge models.MFAChallenge
+	if err := uc.mfaChallenges.FindOneAndUpdate(ctx, bson.M{"_id": claims.Id}, update, opts).Decode(&challenge); err != nil {
+		return false, fmt.Errorf("counting MFA attempt: %w", err)
+	}
+	return challenge.Attempts <= maxMFAAttempts, nil
+}
+
+// closeMFAChallenge uses up an answered challenge so its mfa_token cannot
+// sign in a second time.
+func (uc *UserController) closeMFAChallenge(ctx context.Context, id string) error {
+	_, err := uc.mfaChallenges.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"attempts": maxMFAAttempts}})
+	if err != nil {
+		return fmt.Errorf("closing MFA challenge: %w", err)
+	}
+	return nil
+}
+
+func (uc *UserController) consumeRecoveryCode(ctx context.Context, userID, code string) (bool, error) {
+	hash := helpers.HashRecoveryCode(code)
+	result, err := uc.userCollection.UpdateOne(ctx,
+		bson.M{"user_id": userID, "recovery_codes": hash},
+		bson.M{"$pull": bson.M{"recovery_codes": hash}, "$set": bson.M{"updated_at": time.Now()}},
+	)
+	if err != nil {
+		return false, fmt.Errorf("consuming recovery code: %w", err)
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+func (uc *UserController) securitySettings(ctx context.Context) (models.SecuritySettings, error) {
+	settings := models.SecuritySettings{ID: models.SecuritySettingsID}
+	err := uc.settingsCollection.FindOne(ctx, bson.M{"_id": models.SecuritySettingsID}).Decode(&settings)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return settings, fmt.Errorf("finding security settings: %w", err)
+	}
+	return settings, nil
+}
+
+// adminMFAPending reports whether an admin must still enroll in MFA before
+// their tokens may be used for admin-only actions.
+func (uc *UserController) adminMFAPending(ctx context.Context, user *models.User) (bool, error) {
+	if user.UserType == nil || *user.UserType != helpers.AdminRole || user.MFAEnabled {
+		return false, nil
+	}
+	settings, err := uc.securitySettings(ctx)
+	if err != nil {
+		return false, err
+	}
+	return settings.RequireAdminMFA, nil
+}
@@ -4,15 +4,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 	"github.com/mayurvarma14/go-movie-review/database"
+	"github.com/mayurvarma14/go-movie-review/dto"
 	"github.com/mayurvarma14/go-movie-review/helpers"
+	"github.com/mayurvarma14/go-movie-review/internals/apperrors"
 	"github.com/mayurvarma14/go-movie-review/models"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
@@ -21,59 +23,65 @@ import (
 
 type MovieController struct {
 	movieCollection *mongo.Collection
+	genreCollection *mongo.Collection
+	auditCollection *mongo.Collection
 	validate        *validator.Validate
 }
 
 func NewMovieController(db *database.Database) *MovieController {
 	return &MovieController{
 		movieCollection: db.Client.Database(db.Name).Collection("movie"),
-		validate:        validator.New(),
+		genreCollection: db.Client.Database(db.Name).Collection("genre"),
+		auditCollection: db.Client.Database(db.Name).Collection("audit"),
+		validate:        helpers.NewValidator(),
 	}
 }
 
 func (mc *MovieController) CreateMovie() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if err := helpers.VerifyUserType(c, helpers.AdminRole); err != nil {
-			helpers.HandleError(c, http.StatusForbidden, err)
+			helpers.HandleError(c, err)
 			return
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		ctx, cancel := helpers.RequestContext(c)
 		defer cancel()
 
-		var movie models.Movie
-		if err := c.BindJSON(&movie); err != nil {
-			helpers.HandleError(c, http.StatusBadRequest, fmt.Errorf("binding JSON: %w", err))
+		var req dto.MovieRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			helpers.HandleError(c, apperrors.InvalidInput(err))
 			return
 		}
 
-		if err := mc.validate.Struct(&movie); err != nil {
-			helpers.HandleError(c, http.StatusBadRequest, fmt.Errorf("validation: %w", err))
+		if err := mc.validate.Struct(&req); err != nil {
+			helpers.HandleError(c, apperrors.InvalidInput(err))
 			return
 		}
 
-		count, err := mc.movieCollection.CountDocuments(ctx, bson.M{"name": bson.M{"$regex": *movie.Name, "$options": "i"}})
+		count, err := mc.movieCollection.CountDocuments(ctx, helpers.NotDeleted(bson.M{"name": bson.M{"$regex": req.Name, "$options": "i"}}))
 		if err != nil {
-			log.Printf("Error checking movie: %v", err)
-			helpers.HandleError(c, http.StatusInternalServerError, fmt.Errorf("checking movie: %w", err))
+			helpers.HandleError(c, apperrors.Internal(fmt.Errorf("checking movie: %w", err)))
 			return
 		}
 		if count > 0 {
-			helpers.HandleError(c, http.StatusBadRequest, errors.New("movie already exists"))
+			helpers.HandleError(c, apperrors.Conflict("movie already exists"))
 			return
 		}
 
+		movie := req.Model()
 		movie.ID = bson.NewObjectID()
 		movie.CreatedAt = time.Now()
 		movie.UpdatedAt = time.Now()
+		movie.Version = 1
 
-		result, err := mc.movieCollection.InsertOne(ctx, movie)
-		if err != nil {
-			helpers.HandleError(c, http.StatusInternalServerError, fmt.Errorf("inserting movie: %w", err))
+		if _, err := mc.movieCollection.InsertOne(ctx, movie); err != nil {
+			helpers.HandleError(c, apperrors.Internal(fmt.Errorf("inserting movie: %w", err)))
 			return
 		}
+		helpers.RecordAudit(ctx, c, mc.auditCollection, helpers.AuditCreate, "movie", strconv.Itoa(movie.MovieID), nil, movie)
 
-		c.JSON(http.StatusCreated, gin.H{"message": "Movie created successfully", "movie_id": movie.MovieID, "ID": result.InsertedID})
+		c.Header("ETag", helpers.ETag(movie.Version))
+		c.JSON(http.StatusCreated, gin.H{"message": "Movie created successfully", "movie_id": movie.MovieID})
 	}
 }
 
@@ -82,41 +90,44 @@ func (mc *MovieController) GetMovie() gin.HandlerFunc {
 		movieIDStr := c.Param("movie_id")
 		movieID, err := strconv.Atoi(movieIDStr)
 		if err != nil {
-			helpers.HandleError(c, http.StatusBadRequest, fmt.Errorf("invalid movie ID: %w", err))
+			helpers.HandleError(c, apperrors.Validation("invalid movie ID"))
 			return
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		ctx, cancel := helpers.RequestContext(c)
 		defer cancel()
 
 		var movie models.Movie
-		err = mc.movieCollection.FindOne(ctx, bson.M{"movie_id": movieID}).Decode(&movie)
+		err = mc.movieCollection.FindOne(ctx, helpers.NotDeleted(bson.M{"movie_id": movieID})).Decode(&movie)
 		if err != nil {
 			if errors.Is(err, mongo.ErrNoDocuments) {
-				helpers.HandleError(c, http.StatusNotFound, errors.New("movie not found"))
+				helpers.HandleError(c, apperrors.NotFound("movie not found"))
 			} else {
-				helpers.HandleError(c, http.StatusInternalServerError, fmt.Errorf("finding movie: %w", err))
+				helpers.HandleError(c, apperrors.Internal(fmt.Errorf("finding movie: %w", err)))
 			}
 			return
 		}
 
-		c.JSON(http.StatusOK, movie)
+		if helpers.ConditionalGet(c, movie.Version) {
+			return
+		}
+		c.JSON(http.StatusOK, dto.NewMovieResponse(&movie))
 	}
 }
 
 func (mc *MovieController) GetMovies() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		ctx, cancel := helpers.RequestContext(c)
 		defer cancel()
 
 		page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
 		if err != nil || page < 1 {
-			helpers.HandleError(c, http.StatusBadRequest, errors.New("invalid page number"))
+			helpers.HandleError(c, apperrors.Validation("invalid page number"))
 			return
 		}
 		limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
 		if err != nil || limit < 1 {
-			helpers.HandleError(c, http.StatusBadRequest, errors.New("invalid limit number"))
+			helpers.HandleError(c, apperrors.Validation("invalid limit number"))
 			return
 		}
 		skip := (page - 1) * limit
@@ -125,100 +136,186 @@ func (mc *MovieController) GetMovies() gin.HandlerFunc {
 		findOptions.SetSkip(int64(skip))
 		findOptions.SetLimit(int64(limit))
 
-		cursor, err := mc.movieCollection.Find(ctx, bson.M{}, findOptions)
+		cursor, err := mc.movieCollection.Find(ctx, helpers.NotDeleted(bson.M{}), findOptions)
 		if err != nil {
-			helpers.HandleError(c, http.StatusInternalServerError, fmt.Errorf("finding movies: %w", err))
+			helpers.HandleError(c, apperrors.Internal(fmt.Errorf("finding movies: %w", err)))
 			return
 		}
 		defer cursor.Close(ctx)
 
 		var movies []models.Movie
 		if err := cursor.All(ctx, &movies); err != nil {
-			helpers.HandleError(c, http.StatusInternalServerError, fmt.Errorf("decoding movies: %w", err))
+			helpers.HandleError(c, apperrors.Internal(fmt.Errorf("decoding movies: %w", err)))
 			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{"movies": movies})
+		c.JSON(http.StatusOK, gin.H{"movies": dto.NewMovieResponses(movies)})
 	}
 }
 
 func (mc *MovieController) UpdateMovie() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if err := helpers.VerifyUserType(c, helpers.AdminRole); err != nil {
-			helpers.HandleError(c, http.StatusForbidden, err)
+			helpers.HandleError(c, err)
 			return
 		}
 
 		movieIDStr := c.Param("movie_id")
 		movieID, err := strconv.Atoi(movieIDStr)
 		if err != nil {
-			helpers.HandleError(c, http.StatusBadRequest, fmt.Errorf("invalid movie ID: %w", err))
+			helpers.HandleError(c, apperrors.Validation("invalid movie ID"))
 			return
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		ctx, cancel := helpers.RequestContext(c)
 		defer cancel()
 
-		var updatedMovie models.Movie
-		if err := c.BindJSON(&updatedMovie); err != nil {
-			helpers.HandleError(c, http.StatusBadRequest, fmt.Errorf("binding JSON: %w", err))
+		var req dto.MovieRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			helpers.HandleError(c, apperrors.InvalidInput(err))
 			return
 		}
-		if err := mc.validate.Struct(&updatedMovie); err != nil {
-			helpers.HandleError(c, http.StatusBadRequest, fmt.Errorf("validation error: %w", err))
+		if err := mc.validate.Struct(&req); err != nil {
+			helpers.HandleError(c, apperrors.InvalidInput(err))
 			return
 		}
 
-		update := bson.M{
-			"$set": bson.M{
-				"name":       updatedMovie.Name,
-				"topic":      updatedMovie.Topic,
-				"genre_id":   updatedMovie.GenreID,
-				"movie_url":  updatedMovie.MovieURL,
-				"updated_at": time.Now(),
-			},
-		}
+		versions := helpers.IfMatch(c)
+		filter := helpers.MatchVersions(helpers.NotDeleted(bson.M{"movie_id": movieID}), versions)
+		update := helpers.VersionedUpdate(movieFields(&req))
+		opts := options.FindOneAndUpdate().SetReturnDocument(options.Before)
 
-		result, err := mc.movieCollection.UpdateOne(ctx, bson.M{"movie_id": movieID}, update)
+		var before models.Movie
+		err = mc.movieCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&before)
 		if err != nil {
-			helpers.HandleError(c, http.StatusInternalServerError, fmt.Errorf("updating movie: %w", err))
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				helpers.HandleError(c, helpers.MissedWrite(ctx, mc.movieCollection, filter, versions, "movie not found"))
+			} else {
+				helpers.HandleError(c, apperrors.Internal(fmt.Errorf("updating movie: %w", err)))
+			}
 			return
 		}
 
-		if result.MatchedCount == 0 {
-			helpers.HandleError(c, http.StatusNotFound, errors.New("movie not found"))
+		movie, err := helpers.Updated(&before, update)
+		if err != nil {
+			helpers.HandleError(c, apperrors.Internal(err))
 			return
 		}
+		helpers.RecordAudit(ctx, c, mc.auditCollection, helpers.AuditUpdate, "movie", strconv.Itoa(movieID), before, movie)
 
+		c.Header("ETag", helpers.ETag(movie.Version))
 		c.JSON(http.StatusOK, gin.H{"message": "Movie updated successfully"})
 	}
 }
 
+// PatchMovie applies a JSON merge patch to a movie and answers with the
+// result. The merged movie must pass the same validation as a PUT body.
+func (mc *MovieController) PatchMovie() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := helpers.VerifyUserType(c, helpers.AdminRole); err != nil {
+			helpers.HandleError(c, err)
+			return
+		}
+
+		movieID, err := strconv.Atoi(c.Param("movie_id"))
+		if err != nil {
+			helpers.HandleError(c, apperrors.Validation("invalid movie ID"))
+			return
+		}
+
+		ctx, cancel := helpers.RequestContext(c)
+		defer cancel()
+
+		var movie models.Movie
+		err = mc.movieCollection.FindOne(ctx, helpers.NotDeleted(bson.M{"movie_id": movieID})).Decode(&movie)
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				helpers.HandleError(c, apperrors.NotFound("movie not found"))
+			} else {
+				helpers.HandleError(c, apperrors.Internal(fmt.Errorf("finding movie: %w", err)))
+			}
+			return
+		}
+
+		versions := helpers.IfMatch(c)
+		if !helpers.VersionMatches(movie.Version, versions) {
+			helpers.HandleError(c, apperrors.PreconditionFailed("resource was modified since it was fetched"))
+			return
+		}
+
+		req, err := helpers.BindMergePatch(c, dto.NewMovieRequest(&movie))
+		if err != nil {
+			helpers.HandleError(c, err)
+			return
+		}
+		if err := mc.validate.Struct(&req); err != nil {
+			helpers.HandleError(c, apperrors.InvalidInput(err))
+			return
+		}
+		if req.MovieID != movieID {
+			helpers.HandleError(c, apperrors.Validation("movie_id cannot be changed"))
+			return
+		}
+
+		// Pin the write to the version the patch was applied to, so a
+		// concurrent edit in between fails instead of being overwritten.
+		pinned := []int64{movie.Version}
+		filter := helpers.MatchVersions(helpers.NotDeleted(bson.M{"movie_id": movieID}), pinned)
+		opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+		var patched models.Movie
+		err = mc.movieCollection.FindOneAndUpdate(ctx, filter, helpers.VersionedUpdate(movieFields(&req)), opts).Decode(&patched)
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				helpers.HandleError(c, helpers.MissedWrite(ctx, mc.movieCollection, filter, pinned, "movie not found"))
+			} else {
+				helpers.HandleError(c, apperrors.Internal(fmt.Errorf("updating movie: %w", err)))
+			}
+			return
+		}
+		helpers.RecordAudit(ctx, c, mc.auditCollection, helpers.AuditUpdate, "movie", strconv.Itoa(movieID), movie, patched)
+
+		c.Header("ETag", helpers.ETag(patched.Version))
+		c.JSON(http.StatusOK, dto.NewMovieResponse(&patched))
+	}
+}
+
+// movieFields lists what PUT and PATCH may change; movie_id stays fixed.
+func movieFields(req *dto.MovieRequest) bson.M {
+	return bson.M{
+		"name":       req.Name,
+		"topic":      req.Topic,
+		"genre_id":   req.GenreID,
+		"movie_url":  req.MovieURL,
+		"updated_at": time.Now(),
+	}
+}
+
 func (mc *MovieController) SearchMovieByQuery() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		query := c.Query("name")
 		if query == "" {
-			helpers.HandleError(c, http.StatusBadRequest, errors.New("search query parameter 'name' is required"))
+			helpers.HandleError(c, apperrors.Validation("search query parameter 'name' is required"))
 			return
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		ctx, cancel := helpers.RequestContext(c)
 		defer cancel()
 
 		var movies []models.Movie
-		cursor, err := mc.movieCollection.Find(ctx, bson.M{"name": bson.M{"$regex": query, "$options": "i"}})
+		cursor, err := mc.movieCollection.Find(ctx, helpers.NotDeleted(bson.M{"name": bson.M{"$regex": query, "$options": "i"}}))
 		if err != nil {
-			helpers.HandleError(c, http.StatusInternalServerError, fmt.Errorf("searching movies: %w", err))
+			helpers.HandleError(c, apperrors.Internal(fmt.Errorf("searching movies: %w", err)))
 			return
 		}
 		defer cursor.Close(ctx)
 
 		if err := cursor.All(ctx, &movies); err != nil {
-			helpers.HandleError(c, http.StatusInternalServerError, fmt.Errorf("decoding movies: %w", err))
+			helpers.HandleError(c, apperrors.Internal(fmt.Errorf("decoding movies: %w", err)))
 			return
 		}
 
-		c.JSON(http.StatusOK, movies)
+		c.JSON(http.StatusOK, dto.NewMovieResponses(movies))
 	}
 }
 
@@ -227,34 +324,34 @@ func (mc *MovieController) SearchMovieByGenre() gin.HandlerFunc {
 		genreIDStr := c.Query("genre_id")
 		genreID, err := strconv.Atoi(genreIDStr)
 		if err != nil {
-			helpers.HandleError(c, http.StatusBadRequest, fmt.Errorf("invalid genre ID: %w", err))
+			helpers.HandleError(c, apperrors.Validation("invalid genre ID"))
 			return
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		ctx, cancel := helpers.RequestContext(c)
 		defer cancel()
 
 		var movies []models.Movie
-		cursor, err := mc.movieCollection.Find(ctx, bson.M{"genre_id": genreID})
+		cursor, err := mc.movieCollection.Find(ctx, helpers.NotDeleted(bson.M{"genre_id": genreID}))
 		if err != nil {
-			helpers.HandleError(c, http.StatusInternalServerError, fmt.Errorf("searching movies by genre: %w", err))
+			helpers.HandleError(c, apperrors.Internal(fmt.Errorf("searching movies by genre: %w", err)))
 			return
 		}
 		defer cursor.Close(ctx)
 
 		if err := cursor.All(ctx, &movies); err != nil {
-			helpers.HandleError(c, http.StatusInternalServerError, fmt.Errorf("decoding movies: %w", err))
+			helpers.HandleError(c, apperrors.Internal(fmt.Errorf("decoding movies: %w", err)))
 			return
 		}
 
-		c.JSON(http.StatusOK, movies)
+		c.JSON(http.StatusOK, dto.NewMovieResponses(movies))
 	}
 }
 
 func (mc *MovieController) DeleteMovie() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if err := helpers.VerifyUserType(c, helpers.AdminRole); err != nil {
-			helpers.HandleError(c, http.StatusForbidden, err)
+			helpers.HandleError(c, err)
 			return
 		}
 
@@ -262,24 +359,393 @@ func (mc *MovieController) DeleteMovie() gin.HandlerFunc {
 		movieID, err := strconv.Atoi(movieIDStr)
 
 		if err != nil {
-			helpers.HandleError(c, http.StatusBadRequest, fmt.Errorf("invalid movie ID: %w", err))
+			helpers.HandleError(c, apperrors.Validation("invalid movie ID"))
 			return
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		ctx, cancel := helpers.RequestContext(c)
 		defer cancel()
 
-		result, err := mc.movieCollection.DeleteOne(ctx, bson.M{"movie_id": movieID})
+		versions := helpers.IfMatch(c)
+		filter := helpers.MatchVersions(helpers.NotDeleted(bson.M{"movie_id": movieID}), versions)
+		update := helpers.SoftDelete(c.GetString("uid"))
+		opts := options.FindOneAndUpdate().SetReturnDocument(options.Before)
+
+		var before models.Movie
+		err = mc.movieCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&before)
 		if err != nil {
-			helpers.HandleError(c, http.StatusInternalServerError, fmt.Errorf("deleting movie: %w", err))
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				helpers.HandleError(c, helpers.MissedWrite(ctx, mc.movieCollection, filter, versions, "movie not found"))
+			} else {
+				helpers.HandleError(c, apperrors.Internal(fmt.Errorf("deleting movie: %w", err)))
+			}
 			return
 		}
 
-		if result.DeletedCount == 0 {
-			helpers.HandleError(c, http.StatusNotFound, errors.New("movie not found"))
+		after, err := helpers.Updated(&before, update)
+		if err != nil {
+			helpers.HandleError(c, apperrors.Internal(err))
 			return
 		}
+		helpers.RecordAudit(ctx, c, mc.auditCollection, helpers.AuditDelete, "movie", strconv.Itoa(movieID), before, after)
 
 		c.JSON(http.StatusOK, gin.H{"message": "Movie deleted successfully"})
 	}
 }
+
+// GetDeletedMovies lists movies in the trash, most recently deleted first.
+func (mc *MovieController) GetDeletedMovies() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := helpers.VerifyUserType(c, helpers.AdminRole); err != nil {
+			helpers.HandleError(c, err)
+			return
+		}
+
+		ctx, cancel := helpers.RequestContext(c)
+		defer cancel()
+
+		page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+		if err != nil || page < 1 {
+			helpers.HandleError(c, apperrors.Validation("invalid page number"))
+			return
+		}
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+		if err != nil || limit < 1 {
+			helpers.HandleError(c, apperrors.Validation("invalid limit number"))
+			return
+		}
+		skip := (page - 1) * limit
+
+		findOptions := options.Find()
+		findOptions.SetSort(bson.D{{Key: "deleted_at", Value: -1}})
+		findOptions.SetSkip(int64(skip))
+		findOptions.SetLimit(int64(limit))
+
+		cursor, err := mc.movieCollection.Find(ctx, helpers.Trashed(bson.M{}), findOptions)
+		if err != nil {
+			helpers.HandleError(c, apperrors.Internal(fmt.Errorf("finding deleted movies: %w", err)))
+			return
+		}
+		defer cursor.Close(ctx)
+
+		var movies []models.Movie
+		if err := cursor.All(ctx, &movies); err != nil {
+			helpers.HandleError(c, apperrors.Internal(fmt.Errorf("decoding movies: %w", err)))
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"movies": dto.NewMovieResponses(movies)})
+	}
+}
+
+// RestoreMovie takes a movie out of the trash. It refuses when another movie
+// has taken over the movie_id in the meantime.
+func (mc *MovieController) RestoreMovie() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := helpers.VerifyUserType(c, helpers.AdminRole); err != nil {
+			helpers.HandleError(c, err)
+			return
+		}
+
+		movieID, err := strconv.Atoi(c.Param("movie_id"))
+		if err != nil {
+			helpers.HandleError(c, apperrors.Validation("invalid movie ID"))
+			return
+		}
+
+		ctx, cancel := helpers.RequestContext(c)
+		defer cancel()
+
+		count, err := mc.movieCollection.CountDocuments(ctx, helpers.NotDeleted(bson.M{"movie_id": movieID}))
+		if err != nil {
+			helpers.HandleError(c, apperrors.Internal(fmt.Errorf("checking movie: %w", err)))
+			return
+		}
+		if count > 0 {
+			helpers.HandleError(c, apperrors.Conflict("another movie with this ID exists"))
+			return
+		}
+
+		versions := helpers.IfMatch(c)
+		filter := helpers.MatchVersions(helpers.Trashed(bson.M{"movie_id": movieID}), versions)
+		update := helpers.Restore()
+		opts := options.FindOneAndUpdate().SetReturnDocument(options.Before)
+
+		var before models.Movie
+		err = mc.movieCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&before)
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				helpers.HandleError(c, helpers.MissedWrite(ctx, mc.movieCollection, filter, versions, "movie not found in trash"))
+			} else {
+				helpers.HandleError(c, apperrors.Internal(fmt.Errorf("restoring movie: %w", err)))
+			}
+			return
+		}
+
+		movie, err := helpers.Updated(&before, update)
+		if err != nil {
+			helpers.HandleError(c, apperrors.Internal(err))
+			return
+		}
+		helpers.RecordAudit(ctx, c, mc.auditCollection, helpers.AuditRestore, "movie", strconv.Itoa(movieID), before, movie)
+
+		c.Header("ETag", helpers.ETag(movie.Version))
+		c.JSON(http.StatusOK, dto.NewMovieResponse(movie))
+	}
+}
+
+// ImportMovies creates and updates movies from a CSV or NDJSON upload, keyed
+// by movie_id. Rows that match the stored movie are skipped, a row that
+// fails never stops the others, and dry_run=true reports what would happen
+// without writing anything.
+func (mc *MovieController) ImportMovies() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := helpers.VerifyUserType(c, helpers.AdminRole); err != nil {
+			helpers.HandleError(c, err)
+			return
+		}
+
+		dryRun, err := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+		if err != nil {
+			helpers.HandleError(c, apperrors.Validation("dry_run must be true or false"))
+			return
+		}
+
+		rows, err := helpers.ReadImport(c, dto.MovieImportColumns, dto.NewMovieImportRow)
+		if err != nil {
+			helpers.HandleError(c, err)
+			return
+		}
+
+		ctx, cancel := helpers.RequestContext(c)
+		defer cancel()
+
+		report, err := mc.ImportRows(ctx, rows, dryRun, helpers.RequestActor(c))
+		if err != nil {
+			helpers.HandleError(c, apperrors.Internal(err))
+			return
+		}
+
+		c.JSON(http.StatusOK, report)
+	}
+}
+
+// ImportRows does the work of ImportMovies on rows that are already decoded,
+// which lets the admin CLI import files too. Writes are audited as actor's.
+func (mc *MovieController) ImportRows(ctx context.Context, rows []helpers.ImportRow[dto.MovieImportRow], dryRun bool, actor helpers.AuditActor) (dto.ImportReport, error) {
+	results := make([]dto.ImportRowResult, len(rows))
+	seen := make(map[int]bool, len(rows))
+	// Empty rather than nil, which would encode as null for $in.
+	movieIDs, genreIDs, genreNames := []int{}, []int{}, []string{}
+	for i := range rows {
+		row := &rows[i]
+		results[i] = dto.ImportRowResult{Line: row.Line, Status: dto.ImportFailed, MovieID: row.Value.MovieID}
+		if row.Err == nil {
+			row.Err = mc.validate.Struct(&row.Value)
+		}
+		if row.Err == nil && row.Value.MovieID <= 0 {
+			row.Err = errors.New("movie_id is required")
+		}
+		if row.Err == nil && seen[row.Value.MovieID] {
+			row.Err = errors.New("movie_id appears on an earlier line")
+		}
+		if row.Err != nil {
+			results[i].Error = helpers.ImportError(row.Err)
+			continue
+		}
+
+		seen[row.Value.MovieID] = true
+		movieIDs = append(movieIDs, row.Value.MovieID)
+		if row.Value.GenreID != 0 {
+			genreIDs = append(genreIDs, row.Value.GenreID)
+		}
+		if row.Value.Genre != "" {
+			genreNames = append(genreNames, row.Value.Genre)
+		}
+	}
+
+	genres, err := mc.importGenres(ctx, genreIDs, genreNames)
+	if err != nil {
+		return dto.ImportReport{}, err
+	}
+
+	existing := make(map[int]models.Movie, len(movieIDs))
+	cursor, err := mc.movieCollection.Find(ctx, helpers.NotDeleted(bson.M{"movie_id": bson.M{"$in": movieIDs}}))
+	if err != nil {
+		return dto.ImportReport{}, fmt.Errorf("finding movies: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var stored []models.Movie
+	if err := cursor.All(ctx, &stored); err != nil {
+		return dto.ImportReport{}, fmt.Errorf("decoding movies: %w", err)
+	}
+	for _, movie := range stored {
+		existing[movie.MovieID] = movie
+	}
+
+	// writes, their rows and their audit entries share indexes, which is
+	// how bulk write errors point back at rows.
+	var writes []mongo.WriteModel
+	var writeRows []int
+	var changes []helpers.AuditChange
+	for i, row := range rows {
+		if row.Err != nil {
+			continue
+		}
+
+		req := row.Value.MovieRequest
+		genreID, err := resolveGenre(genres, row.Value.GenreID, row.Value.Genre)
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+		req.GenreID = genreID
+		results[i].GenreID = genreID
+
+		before, found := existing[req.MovieID]
+		switch {
+		case !found:
+			movie := req.Model()
+			movie.ID = bson.NewObjectID()
+			movie.CreatedAt = time.Now()
+			movie.UpdatedAt = movie.CreatedAt
+			movie.Version = 1
+			writes = append(writes, mongo.NewInsertOneModel().SetDocument(movie))
+			changes = append(changes, helpers.AuditChange{Action: helpers.AuditCreate, ResourceID: strconv.Itoa(req.MovieID), After: movie})
+			results[i].Status = dto.ImportCreated
+		case dto.NewMovieRequest(&before) == req:
+			results[i].Status = dto.ImportSkipped
+			continue
+		default:
+			update := helpers.VersionedUpdate(movieFields(&req))
+			after, err := helpers.Updated(&before, update)
+			if err != nil {
+				return dto.ImportReport{}, err
+			}
+			writes = append(writes, mongo.NewUpdateOneModel().SetFilter(helpers.NotDeleted(bson.M{"movie_id": req.MovieID})).SetUpdate(update))
+			changes = append(changes, helpers.AuditChange{Action: helpers.AuditUpdate, ResourceID: strconv.Itoa(req.MovieID), Before: before, After: after})
+			results[i].Status = dto.ImportUpdated
+		}
+		writeRows = append(writeRows, i)
+	}
+
+	if !dryRun && len(writes) > 0 {
+		failed, err := helpers.BulkWrite(ctx, mc.movieCollection, writes)
+		if err != nil {
+			return dto.ImportReport{}, fmt.Errorf("importing movies: %w", err)
+		}
+
+		var written []helpers.AuditChange
+		for w, i := range writeRows {
+			if err, ok := failed[w]; ok {
+				results[i].Status = dto.ImportFailed
+				results[i].Error = helpers.ImportWriteError(err, "movie_id")
+				continue
+			}
+			written = append(written, changes[w])
+		}
+		helpers.RecordAuditsAs(ctx, actor, mc.auditCollection, "movie", written)
+	}
+
+	return dto.NewImportReport(dryRun, results), nil
+}
+
+// importGenres finds the live genres import rows refer to, by genre_id or
+// by name ignoring case.
+func (mc *MovieController) importGenres(ctx context.Context, ids []int, names []string) (genreLookup, error) {
+	lookup := genreLookup{ids: map[int]bool{}, names: map[string]int{}}
+	if len(ids) == 0 && len(names) == 0 {
+		return lookup, nil
+	}
+
+	filter := helpers.NotDeleted(bson.M{"$or": bson.A{
+		bson.M{"genre_id": bson.M{"$in": ids}},
+		bson.M{"name": bson.M{"$in": names}},
+	}})
+	opts := options.Find().SetCollation(&options.Collation{Locale: "en", Strength: 2})
+	cursor, err := mc.genreCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return lookup, fmt.Errorf("finding genres: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var genres []models.Genre
+	if err := cursor.All(ctx, &genres); err != nil {
+		return lookup, fmt.Errorf("decoding genres: %w", err)
+	}
+	for _, genre := range genres {
+		lookup.ids[genre.GenreID] = true
+		if genre.Name != nil {
+			lookup.names[strings.ToLower(*genre.Name)] = genre.GenreID
+		}
+	}
+	return lookup, nil
+}
+
+type genreLookup struct {
+	ids   map[int]bool
+	names map[string]int
+}
+
+// resolveGenre picks the genre_id for an import row from its genre_id, its
+// genre name, or both when they agree. A row with neither has no genre.
+func resolveGenre(lookup genreLookup, id int, name string) (int, error) {
+	if name != "" {
+		named, ok := lookup.names[strings.ToLower(name)]
+		if !ok {
+			return 0, fmt.Errorf("genre %q does not exist", name)
+		}
+		if id != 0 && id != named {
+			return 0, fmt.Errorf("genre %q does not have genre_id %d", name, id)
+		}
+		return named, nil
+	}
+	if id != 0 && !lookup.ids[id] {
+		return 0, fmt.Errorf("genre_id %d does not exist", id)
+	}
+	return id, nil
+}
+
+// ExportMovies streams the catalog's movies as CSV, JSON or NDJSON. name and
+// genre_id narrow it as they do for search and filter.
+func (mc *MovieController) ExportMovies() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := helpers.VerifyUserType(c, helpers.AdminRole); err != nil {
+			helpers.HandleError(c, err)
+			return
+		}
+
+		format, err := helpers.ExportFormat(c)
+		if err != nil {
+			helpers.HandleError(c, err)
+			return
+		}
+
+		filter := bson.M{}
+		if name := c.Query("name"); name != "" {
+			filter["name"] = bson.M{"$regex": name, "$options": "i"}
+		}
+		if genreIDStr := c.Query("genre_id"); genreIDStr != "" {
+			genreID, err := strconv.Atoi(genreIDStr)
+			if err != nil {
+				helpers.HandleError(c, apperrors.Validation("invalid genre ID"))
+				return
+			}
+			filter["genre_id"] = genreID
+		}
+
+		ctx, cancel := helpers.RequestContext(c)
+		defer cancel()
+
+		findOptions := options.Find().SetSort(bson.D{{Key: "movie_id", Value: 1}})
+		cursor, err := mc.movieCollection.Find(ctx, helpers.NotDeleted(filter), findOptions)
+		if err != nil {
+			helpers.HandleError(c, apperrors.Internal(fmt.Errorf("finding movies: %w", err)))
+			return
+		}
+		defer cursor.Close(ctx)
+
+		helpers.StreamExport(ctx, c, cursor, format, "movies", dto.MovieExportColumns, dto.NewMovieResponse)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"strconv"
 	"time"
@@ -11,21 +12,28 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 	"github.com/mayurvarma14/go-movie-review/database"
+	"github.com/mayurvarma14/go-movie-review/dto"
 	"github.com/mayurvarma14/go-movie-review/helpers"
+	"github.com/mayurvarma14/go-movie-review/internals/apperrors"
 	"github.com/mayurvarma14/go-movie-review/models"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
 type ReviewController struct {
 	reviewCollection *mongo.Collection
+	movieCollection  *mongo.Collection
+	auditCollection  *mongo.Collection
 	validate         *validator.Validate
 }
 
 func NewReviewController(db *database.Database) *ReviewController {
 	return &ReviewController{
 		reviewCollection: db.Client.Database(db.Name).Collection("review"),
-		validate:         validator.New(),
+		movieCollection:  db.Client.Database(db.Name).Collection("movie"),
+		auditCollection:  db.Client.Database(db.Name).Collection("audit"),
+		validate:         helpers.NewValidator(),
 	}
 }
 
@@ -33,46 +41,49 @@ func (rc *ReviewController) AddReview() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userType := c.GetString("user_type")
 		if userType != helpers.UserRole {
-			helpers.HandleError(c, http.StatusForbidden, errors.New("only users can add reviews"))
+			helpers.HandleError(c, apperrors.Forbidden("only users can add reviews"))
 			return
 		}
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		ctx, cancel := helpers.RequestContext(c)
 		defer cancel()
 
-		var review models.Reviews
-		if err := c.BindJSON(&review); err != nil {
-			helpers.HandleError(c, http.StatusBadRequest, fmt.Errorf("binding JSON: %w", err))
+		var req dto.ReviewRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			helpers.HandleError(c, apperrors.InvalidInput(err))
 			return
 		}
 
-		if err := rc.validate.Struct(&review); err != nil {
-			helpers.HandleError(c, http.StatusBadRequest, fmt.Errorf("validation: %w", err))
+		if err := rc.validate.Struct(&req); err != nil {
+			helpers.HandleError(c, apperrors.InvalidInput(err))
 			return
 		}
 
 		reviewerID := c.GetString("uid") // Get reviewer ID from JWT
 		if reviewerID == "" {
-			helpers.HandleError(c, http.StatusInternalServerError, errors.New("reviewer ID not found in token"))
+			helpers.HandleError(c, apperrors.Internal(errors.New("reviewer ID not found in token")))
 			return
 		}
 		objectReviewerID, err := bson.ObjectIDFromHex(reviewerID)
 		if err != nil {
-			helpers.HandleError(c, http.StatusBadRequest, errors.New("invalid reviewer ID format"))
+			helpers.HandleError(c, apperrors.Validation("invalid reviewer ID format"))
 			return
 		}
 
+		review := req.Model()
 		review.ID = bson.NewObjectID()
 		review.ReviewerID = objectReviewerID // Use the extracted ID
 		review.CreatedAt = time.Now()
 		review.UpdatedAt = time.Now()
+		review.Version = 1
 
-		_, err = rc.reviewCollection.InsertOne(ctx, review)
-		if err != nil {
-			helpers.HandleError(c, http.StatusInternalServerError, fmt.Errorf("inserting review: %w", err))
+		if _, err := rc.reviewCollection.InsertOne(ctx, review); err != nil {
+			helpers.HandleError(c, apperrors.Internal(fmt.Errorf("inserting review: %w", err)))
 			return
 		}
+		rc.countReviews(ctx, review.MovieID, 1)
 
-		c.JSON(http.StatusCreated, gin.H{"message": "Review added successfully"})
+		c.Header("ETag", helpers.ETag(review.Version))
+		c.JSON(http.StatusCreated, gin.H{"message": "Review added successfully", "review_id": review.ID.Hex()})
 	}
 }
 
@@ -81,27 +92,27 @@ func (rc *ReviewController) ViewAMovieReviews() gin.HandlerFunc {
 		movieIDStr := c.Query("movie_id")
 		movieID, err := strconv.Atoi(movieIDStr)
 		if err != nil {
-			helpers.HandleError(c, http.StatusBadRequest, fmt.Errorf("invalid movie ID: %w", err))
+			helpers.HandleError(c, apperrors.Validation("invalid movie ID"))
 			return
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		ctx, cancel := helpers.RequestContext(c)
 		defer cancel()
 
 		var reviews []models.Reviews
-		cursor, err := rc.reviewCollection.Find(ctx, bson.M{"movie_id": movieID})
+		cursor, err := rc.reviewCollection.Find(ctx, helpers.NotDeleted(bson.M{"movie_id": movieID}))
 		if err != nil {
-			helpers.HandleError(c, http.StatusInternalServerError, fmt.Errorf("finding reviews: %w", err))
+			helpers.HandleError(c, apperrors.Internal(fmt.Errorf("finding reviews: %w", err)))
 			return
 		}
 		defer cursor.Close(ctx)
 
 		if err := cursor.All(ctx, &reviews); err != nil {
-			helpers.HandleError(c, http.StatusInternalServerError, fmt.Errorf("decoding reviews: %w", err))
+			helpers.HandleError(c, apperrors.Internal(fmt.Errorf("decoding reviews: %w", err)))
 			return
 		}
 
-		c.JSON(http.StatusOK, reviews)
+		c.JSON(http.StatusOK, dto.NewReviewResponses(reviews))
 	}
 }
 
@@ -110,21 +121,21 @@ func (rc *ReviewController) DeleteReview() gin.HandlerFunc {
 		reviewIDStr := c.Param("id")
 		reviewID, err := bson.ObjectIDFromHex(reviewIDStr) // Use ParseObjectID
 		if err != nil {
-			helpers.HandleError(c, http.StatusBadRequest, fmt.Errorf("invalid review ID format: %w", err))
+			helpers.HandleError(c, apperrors.Validation("invalid review ID format"))
 			return
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		ctx, cancel := helpers.RequestContext(c)
 		defer cancel()
 
 		// Find the review to check ownership
 		var review models.Reviews
-		err = rc.reviewCollection.FindOne(ctx, bson.M{"_id": reviewID}).Decode(&review)
+		err = rc.reviewCollection.FindOne(ctx, helpers.NotDeleted(bson.M{"_id": reviewID})).Decode(&review)
 		if err != nil {
 			if errors.Is(err, mongo.ErrNoDocuments) {
-				helpers.HandleError(c, http.StatusNotFound, errors.New("review not found"))
+				helpers.HandleError(c, apperrors.NotFound("review not found"))
 			} else {
-				helpers.HandleError(c, http.StatusInternalServerError, fmt.Errorf("finding review: %w", err))
+				helpers.HandleError(c, apperrors.Internal(fmt.Errorf("finding review: %w", err)))
 			}
 			return
 		}
@@ -133,24 +144,45 @@ func (rc *ReviewController) DeleteReview() gin.HandlerFunc {
 		reviewerID := c.GetString("uid")
 		objectReviewerID, err := bson.ObjectIDFromHex(reviewerID) // Use ParseObjectID
 		if err != nil {
-			helpers.HandleError(c, http.StatusBadRequest, fmt.Errorf("invalid reviewer ID: %w", err))
+			helpers.HandleError(c, apperrors.Validation("invalid reviewer ID"))
 			return
 		}
-		if review.ReviewerID != objectReviewerID && c.GetString("user_type") != helpers.AdminRole {
-			helpers.HandleError(c, http.StatusForbidden, errors.New("unauthorized to delete this review"))
+		if review.ReviewerID != objectReviewerID && helpers.VerifyUserType(c, helpers.AdminRole) != nil {
+			helpers.HandleError(c, apperrors.Forbidden("unauthorized to delete this review"))
 			return
 		}
 
-		result, err := rc.reviewCollection.DeleteOne(ctx, bson.M{"_id": reviewID})
-		if err != nil {
-			helpers.HandleError(c, http.StatusInternalServerError, fmt.Errorf("deleting review: %w", err))
+		versions := helpers.IfMatch(c)
+		if !helpers.VersionMatches(review.Version, versions) {
+			helpers.HandleError(c, apperrors.PreconditionFailed("resource was modified since it was fetched"))
 			return
 		}
 
-		if result.DeletedCount == 0 {
-			helpers.HandleError(c, http.StatusNotFound, errors.New("review not found")) // Should not happen, but check anyway
+		filter := helpers.MatchVersions(helpers.NotDeleted(bson.M{"_id": reviewID}), versions)
+		update := helpers.SoftDelete(c.GetString("uid"))
+		opts := options.FindOneAndUpdate().SetReturnDocument(options.Before)
+
+		var before models.Reviews
+		err = rc.reviewCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&before)
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				helpers.HandleError(c, helpers.MissedWrite(ctx, rc.reviewCollection, filter, versions, "review not found"))
+			} else {
+				helpers.HandleError(c, apperrors.Internal(fmt.Errorf("deleting review: %w", err)))
+			}
 			return
 		}
+		rc.countReviews(ctx, before.MovieID, -1)
+
+		// Authors removing their own reviews are not administrative actions.
+		if before.ReviewerID != objectReviewerID {
+			after, err := helpers.Updated(&before, update)
+			if err != nil {
+				helpers.HandleError(c, apperrors.Internal(err))
+				return
+			}
+			helpers.RecordAudit(ctx, c, rc.auditCollection, helpers.AuditDelete, "review", reviewID.Hex(), before, after)
+		}
 
 		c.JSON(http.StatusOK, gin.H{"message": "Review deleted successfully"})
 	}
@@ -160,30 +192,180 @@ func (rc *ReviewController) AllUserReviews() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		reviewerID := c.Param("reviewer_id")
 		if err := helpers.MatchUserID(c, reviewerID); err != nil {
-			helpers.HandleError(c, http.StatusUnauthorized, err) // Enforce ownership
+			helpers.HandleError(c, err) // Enforce ownership
 			return
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		ctx, cancel := helpers.RequestContext(c)
 		defer cancel()
 		objectReviewerID, err := bson.ObjectIDFromHex(reviewerID) // Use ParseObjectID
 		if err != nil {
-			helpers.HandleError(c, http.StatusBadRequest, fmt.Errorf("invalid reviewer ID format: %w", err))
+			helpers.HandleError(c, apperrors.Validation("invalid reviewer ID format"))
 			return
 		}
 		var reviews []models.Reviews
-		cursor, err := rc.reviewCollection.Find(ctx, bson.M{"reviewer_id": objectReviewerID})
+		cursor, err := rc.reviewCollection.Find(ctx, helpers.NotDeleted(bson.M{"reviewer_id": objectReviewerID}))
+		if err != nil {
+			helpers.HandleError(c, apperrors.Internal(fmt.Errorf("finding reviews: %w", err)))
+			return
+		}
+		defer cursor.Close(ctx)
+
+		if err := cursor.All(ctx, &reviews); err != nil {
+			helpers.HandleError(c, apperrors.Internal(fmt.Errorf("decoding reviews: %w", err)))
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.NewReviewResponses(reviews))
+	}
+}
+
+// GetDeletedReviews lists reviews in the trash, most recently deleted first.
+func (rc *ReviewController) GetDeletedReviews() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := helpers.VerifyUserType(c, helpers.AdminRole); err != nil {
+			helpers.HandleError(c, err)
+			return
+		}
+
+		ctx, cancel := helpers.RequestContext(c)
+		defer cancel()
+
+		page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+		if err != nil || page < 1 {
+			helpers.HandleError(c, apperrors.Validation("invalid page number"))
+			return
+		}
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+		if err != nil || limit < 1 {
+			helpers.HandleError(c, apperrors.Validation("invalid limit number"))
+			return
+		}
+		skip := (page - 1) * limit
+
+		findOptions := options.Find()
+		findOptions.SetSort(bson.D{{Key: "deleted_at", Value: -1}})
+		findOptions.SetSkip(int64(skip))
+		findOptions.SetLimit(int64(limit))
+
+		cursor, err := rc.reviewCollection.Find(ctx, helpers.Trashed(bson.M{}), findOptions)
 		if err != nil {
-			helpers.HandleError(c, http.StatusInternalServerError, fmt.Errorf("finding reviews: %w", err))
+			helpers.HandleError(c, apperrors.Internal(fmt.Errorf("finding deleted reviews: %w", err)))
 			return
 		}
 		defer cursor.Close(ctx)
 
+		var reviews []models.Reviews
 		if err := cursor.All(ctx, &reviews); err != nil {
-			helpers.HandleError(c, http.StatusInternalServerError, fmt.Errorf("decoding reviews: %w", err))
+			helpers.HandleError(c, apperrors.Internal(fmt.Errorf("decoding reviews: %w", err)))
 			return
 		}
 
-		c.JSON(http.StatusOK, reviews)
+		c.JSON(http.StatusOK, gin.H{"reviews": dto.NewReviewResponses(reviews)})
+	}
+}
+
+// countReviews moves the live movie's review_count by delta. The count is
+// derived from the reviews, so it leaves the movie's version alone, and a
+// failure is logged rather than failing a review write that has already
+// happened; movierev reindex recounts it.
+func (rc *ReviewController) countReviews(ctx context.Context, movieID int, delta int64) {
+	update := bson.M{"$inc": bson.M{"review_count": delta}}
+	if _, err := rc.movieCollection.UpdateOne(ctx, helpers.NotDeleted(bson.M{"movie_id": movieID}), update); err != nil {
+		slog.ErrorContext(ctx, "failed to update review count", "error", err, "movie_id", movieID)
+	}
+}
+
+// RestoreReview takes a review out of the trash.
+func (rc *ReviewController) RestoreReview() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := helpers.VerifyUserType(c, helpers.AdminRole); err != nil {
+			helpers.HandleError(c, err)
+			return
+		}
+
+		reviewID, err := bson.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			helpers.HandleError(c, apperrors.Validation("invalid review ID format"))
+			return
+		}
+
+		ctx, cancel := helpers.RequestContext(c)
+		defer cancel()
+
+		versions := helpers.IfMatch(c)
+		filter := helpers.MatchVersions(helpers.Trashed(bson.M{"_id": reviewID}), versions)
+		update := helpers.Restore()
+		opts := options.FindOneAndUpdate().SetReturnDocument(options.Before)
+
+		var before models.Reviews
+		err = rc.reviewCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&before)
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				helpers.HandleError(c, helpers.MissedWrite(ctx, rc.reviewCollection, filter, versions, "review not found in trash"))
+			} else {
+				helpers.HandleError(c, apperrors.Internal(fmt.Errorf("restoring review: %w", err)))
+			}
+			return
+		}
+		rc.countReviews(ctx, before.MovieID, 1)
+
+		review, err := helpers.Updated(&before, update)
+		if err != nil {
+			helpers.HandleError(c, apperrors.Internal(err))
+			return
+		}
+		helpers.RecordAudit(ctx, c, rc.auditCollection, helpers.AuditRestore, "review", reviewID.Hex(), before, review)
+
+		c.Header("ETag", helpers.ETag(review.Version))
+		c.JSON(http.StatusOK, dto.NewReviewResponse(review))
+	}
+}
+
+// ExportReviews streams reviews as CSV, JSON or NDJSON. movie_id and
+// reviewer_id narrow it as they do for the movie and user review lists.
+func (rc *ReviewController) ExportReviews() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := helpers.VerifyUserType(c, helpers.AdminRole); err != nil {
+			helpers.HandleError(c, err)
+			return
+		}
+
+		format, err := helpers.ExportFormat(c)
+		if err != nil {
+			helpers.HandleError(c, err)
+			return
+		}
+
+		filter := bson.M{}
+		if movieIDStr := c.Query("movie_id"); movieIDStr != "" {
+			movieID, err := strconv.Atoi(movieIDStr)
+			if err != nil {
+				helpers.HandleError(c, apperrors.Validation("invalid movie ID"))
+				return
+			}
+			filter["movie_id"] = movieID
+		}
+		if reviewerIDStr := c.Query("reviewer_id"); reviewerIDStr != "" {
+			reviewerID, err := bson.ObjectIDFromHex(reviewerIDStr)
+			if err != nil {
+				helpers.HandleError(c, apperrors.Validation("invalid reviewer ID format"))
+				return
+			}
+			filter["reviewer_id"] = reviewerID
+		}
+
+		ctx, cancel := helpers.RequestContext(c)
+		defer cancel()
+
+		findOptions := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+		cursor, err := rc.reviewCollection.Find(ctx, helpers.NotDeleted(filter), findOptions)
+		if err != nil {
+			helpers.HandleError(c, apperrors.Internal(fmt.Errorf("finding reviews: %w", err)))
+			return
+		}
+		defer cursor.Close(ctx)
+
+		helpers.StreamExport(ctx, c, cursor, format, "reviews", dto.ReviewExportColumns, dto.NewReviewResponse)
 	}
 }
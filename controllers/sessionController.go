@@ -0,0 +1,81 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mayurvarma14/go-movie-review/database"
+	"github.com/mayurvarma14/go-movie-review/dto"
+	"github.com/mayurvarma14/go-movie-review/helpers"
+	"github.com/mayurvarma14/go-movie-review/internals/apperrors"
+	"github.com/mayurvarma14/go-movie-review/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type SessionController struct {
+	sessionCollection *mongo.Collection
+}
+
+func NewSessionController(db *database.Database) *SessionController {
+	return &SessionController{
+		sessionCollection: db.Client.Database(db.Name).Collection("session"),
+	}
+}
+
+func (sc *SessionController) GetMySessions() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := helpers.RequestContext(c)
+		defer cancel()
+
+		filter := bson.M{
+			"user_id":    c.GetString("uid"),
+			"expires_at": bson.M{"$gt": time.Now()},
+		}
+		findOptions := options.Find().SetSort(bson.D{{Key: "last_seen_at", Value: -1}})
+
+		cursor, err := sc.sessionCollection.Find(ctx, filter, findOptions)
+		if err != nil {
+			helpers.HandleError(c, apperrors.Internal(fmt.Errorf("finding sessions: %w", err)))
+			return
+		}
+		defer cursor.Close(ctx)
+
+		sessions := []models.Session{}
+		if err := cursor.All(ctx, &sessions); err != nil {
+			helpers.HandleError(c, apperrors.Internal(fmt.Errorf("decoding sessions: %w", err)))
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"sessions": dto.NewSessionResponses(sessions, c.GetString("session_id"))})
+	}
+}
+
+func (sc *SessionController) RevokeMySession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sessionID, err := bson.ObjectIDFromHex(c.Param("id"))
+		if err != nil {
+			helpers.HandleError(c, apperrors.Validation("invalid session ID format"))
+			return
+		}
+
+		ctx, cancel := helpers.RequestContext(c)
+		defer cancel()
+
+		result, err := sc.sessionCollection.DeleteOne(ctx, bson.M{"_id": sessionID, "user_id": c.GetString("uid")})
+		if err != nil {
+			helpers.HandleError(c, apperrors.Internal(fmt.Errorf("deleting session: %w", err)))
+			return
+		}
+
+		if result.DeletedCount == 0 {
+			helpers.HandleError(c, apperrors.NotFound("session not found"))
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"message": "Session revoked successfully"})
+	}
+}
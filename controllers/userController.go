@@ -4,7 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"strconv"
 	"time"
@@ -12,7 +12,11 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 	"github.com/mayurvarma14/go-movie-review/database"
+	"github.com/mayurvarma14/go-movie-review/dto"
 	"github.com/mayurvarma14/go-movie-review/helpers"
+	"github.com/mayurvarma14/go-movie-review/internals/apperrors"
+	"github.com/mayurvarma14/go-movie-review/internals/config"
+	"github.com/mayurvarma14/go-movie-review/internals/metrics"
 	"github.com/mayurvarma14/go-movie-review/models"
 	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
@@ -20,124 +24,252 @@ import (
 )
 
 type UserController struct {
-	userCollection *mongo.Collection
-	validate       *validator.Validate
+	userCollection     *mongo.Collection
+	settingsCollection *mongo.Collection
+	sessionCollection  *mongo.Collection
+	auditCollection    *mongo.Collection
+	mfaChallenges      *mongo.Collection
+	validate           *validator.Validate
+	passwordPolicy     helpers.PasswordPolicy
+	tokens             *helpers.TokenManager
 }
 
-func NewUserController(db *database.Database) *UserController {
+func NewUserController(db *database.Database, cfg *config.Config) *UserController {
 	return &UserController{
-		userCollection: db.Client.Database(db.Name).Collection("user"),
-		validate:       validator.New(),
+		userCollection:     db.Client.Database(db.Name).Collection("user"),
+		settingsCollection: db.Client.Database(db.Name).Collection("settings"),
+		sessionCollection:  db.Client.Database(db.Name).Collection("session"),
+		auditCollection:    db.Client.Database(db.Name).Collection("audit"),
+		mfaChallenges:      db.Client.Database(db.Name).Collection("mfa_challenge"),
+		validate:           helpers.NewValidator(),
+		passwordPolicy:     helpers.NewPasswordPolicy(cfg.Password),
+		tokens:             helpers.NewTokenManager(cfg.Auth),
 	}
 }
 
 func (uc *UserController) SignUp() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		ctx, cancel := helpers.RequestContext(c)
 		defer cancel()
 
-		var user models.User
-		if err := c.BindJSON(&user); err != nil {
-			helpers.HandleError(c, http.StatusBadRequest, fmt.Errorf("binding JSON: %w", err))
+		var req dto.SignUpRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			helpers.HandleError(c, apperrors.InvalidInput(err))
 			return
 		}
 
-		if err := uc.validate.Struct(&user); err != nil {
-			helpers.HandleError(c, http.StatusBadRequest, fmt.Errorf("validation: %w", err))
+		user, err := uc.CreateUser(ctx, req)
+		if err != nil {
+			helpers.HandleError(c, err)
 			return
 		}
 
-		emailCount, err := uc.userCollection.CountDocuments(ctx, bson.M{"email": bson.M{"$regex": *user.Email, "$options": "i"}})
-		if err != nil {
-			log.Printf("Error checking email: %v", err)
-			helpers.HandleError(c, http.StatusInternalServerError, fmt.Errorf("checking email: %w", err))
+		c.JSON(http.StatusCreated, gin.H{"message": "User created successfully", "user_id": user.UserID})
+	}
+}
+
+// CreateUser validates req against the same rules as SignUp, including the
+// password policy and unique email and username, and stores the user.
+func (uc *UserController) CreateUser(ctx context.Context, req dto.SignUpRequest) (models.User, error) {
+	if err := uc.validate.Struct(&req); err != nil {
+		return models.User{}, apperrors.InvalidInput(err)
+	}
+
+	if err := uc.passwordPolicy.Validate(req.Password, req.Username, req.Email); err != nil {
+		return models.User{}, apperrors.InvalidInput(err)
+	}
+
+	emailCount, err := uc.userCollection.CountDocuments(ctx, bson.M{"email": bson.M{"$regex": req.Email, "$options": "i"}})
+	if err != nil {
+		return models.User{}, apperrors.Internal(fmt.Errorf("checking email: %w", err))
+	}
+	if emailCount > 0 {
+		return models.User{}, apperrors.Conflict("email already exists")
+	}
+
+	usernameCount, err := uc.userCollection.CountDocuments(ctx, bson.M{"username": bson.M{"$regex": req.Username, "$options": "i"}})
+	if err != nil {
+		return models.User{}, apperrors.Internal(fmt.Errorf("checking username: %w", err))
+	}
+	if usernameCount > 0 {
+		return models.User{}, apperrors.Conflict("username already exists")
+	}
+
+	hashedPassword, err := helpers.MaskPassword(req.Password)
+	if err != nil {
+		return models.User{}, apperrors.Internal(err)
+	}
+	user := req.Model()
+	user.Password = &hashedPassword
+	user.CreatedAt = time.Now()
+	user.UpdatedAt = time.Now()
+	user.ID = bson.NewObjectID()
+	user.UserID = user.ID.Hex()
+
+	if _, err := uc.userCollection.InsertOne(ctx, user); err != nil {
+		return models.User{}, apperrors.Internal(fmt.Errorf("inserting user: %w", err))
+	}
+	return user, nil
+}
+
+func (uc *UserController) Login() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, cancel := helpers.RequestContext(c)
+		defer cancel()
+
+		var loginUser dto.LoginRequest
+		var foundUser models.User
+
+		if err := c.ShouldBindJSON(&loginUser); err != nil {
+			helpers.HandleError(c, apperrors.InvalidInput(err))
 			return
 		}
-		if emailCount > 0 {
-			helpers.HandleError(c, http.StatusBadRequest, errors.New("email already exists"))
+
+		if err := uc.validate.Struct(&loginUser); err != nil {
+			helpers.HandleError(c, apperrors.InvalidInput(err))
 			return
 		}
 
-		usernameCount, err := uc.userCollection.CountDocuments(ctx, bson.M{"username": bson.M{"$regex": *user.Username, "$options": "i"}})
+		err := uc.userCollection.FindOne(ctx, bson.M{"email": bson.M{"$regex": loginUser.Email, "$options": "i"}}).Decode(&foundUser)
 		if err != nil {
-			log.Printf("Error checking username: %v", err)
-			helpers.HandleError(c, http.StatusInternalServerError, fmt.Errorf("checking username: %w", err))
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				metrics.LoginAttempts.WithLabelValues("failure").Inc()
+				helpers.HandleError(c, apperrors.Unauthorized("invalid email or password"))
+			} else {
+				helpers.HandleError(c, apperrors.Internal(fmt.Errorf("finding user: %w", err)))
+			}
 			return
 		}
-		if usernameCount > 0 {
-			helpers.HandleError(c, http.StatusBadRequest, errors.New("username already exists"))
+
+		passwordMatch, err := helpers.ConfirmPassword(*foundUser.Password, loginUser.Password)
+		if err != nil || !passwordMatch {
+			metrics.LoginAttempts.WithLabelValues("failure").Inc()
+			helpers.HandleError(c, apperrors.Unauthorized("invalid email or password"))
 			return
 		}
 
-		hashedPassword, err := helpers.MaskPassword(*user.Password)
-		if err != nil {
-			helpers.HandleError(c, http.StatusInternalServerError, err)
-			return
+		if helpers.PasswordNeedsRehash(*foundUser.Password) {
+			uc.rehashPassword(ctx, foundUser.UserID, loginUser.Password)
 		}
-		user.Password = &hashedPassword
-		user.CreatedAt = time.Now()
-		user.UpdatedAt = time.Now()
-		user.ID = bson.NewObjectID()
-		user.UserID = user.ID.Hex()
 
-		token, refreshToken, err := helpers.GenerateAllTokens(*user.Email, *user.Name, *user.Username, *user.UserType, user.UserID)
-		if err != nil {
-			helpers.HandleError(c, http.StatusInternalServerError, err)
+		if foundUser.MFAEnabled {
+			mfaToken, err := uc.tokens.GenerateMFAToken(foundUser.UserID)
+			if err != nil {
+				helpers.HandleError(c, apperrors.Internal(err))
+				return
+			}
+			metrics.LoginAttempts.WithLabelValues("mfa_required").Inc()
+			c.JSON(http.StatusOK, gin.H{"message": "MFA verification required", "mfa_required": true, "mfa_token": mfaToken})
 			return
 		}
-		user.Token = &token
-		user.RefreshToken = &refreshToken
 
-		result, err := uc.userCollection.InsertOne(ctx, user)
+		mfaPending, err := uc.adminMFAPending(ctx, &foundUser)
 		if err != nil {
-			helpers.HandleError(c, http.StatusInternalServerError, fmt.Errorf("inserting user: %w", err))
+			helpers.HandleError(c, apperrors.Internal(err))
 			return
 		}
 
-		c.JSON(http.StatusCreated, gin.H{"message": "User created successfully", "user_id": result.InsertedID})
+		uc.issueTokens(ctx, c, &foundUser, mfaPending, loginUser.DeviceLabel)
+		if !c.IsAborted() {
+			metrics.LoginAttempts.WithLabelValues("success").Inc()
+		}
 	}
 }
 
-func (uc *UserController) Login() gin.HandlerFunc {
+// rehashPassword upgrades a legacy hash while the plaintext is at hand. It
+// only logs failures since the login itself has already succeeded.
+func (uc *UserController) rehashPassword(ctx context.Context, userID, password string) {
+	hashedPassword, err := helpers.MaskPassword(password)
+	if err != nil {
+		slog.ErrorContext(ctx, "rehashing password failed", "uid", userID, "error", err)
+		return
+	}
+
+	update := bson.M{"$set": bson.M{"password": hashedPassword, "updated_at": time.Now()}}
+	if _, err := uc.userCollection.UpdateOne(ctx, bson.M{"user_id": userID}, update); err != nil {
+		slog.ErrorContext(ctx, "storing rehashed password failed", "uid", userID, "error", err)
+	}
+}
+
+func (uc *UserController) issueTokens(ctx context.Context, c *gin.Context, user *models.User, mfaPending bool, deviceLabel string) {
+	session := helpers.NewSession(c, user.UserID, deviceLabel, uc.tokens.RefreshTokenTTL())
+
+	token, refreshToken, err := uc.tokens.GenerateAllTokens(*user.Email, *user.Name, *user.Username, *user.UserType, user.UserID, session.ID.Hex(), mfaPending)
+	if err != nil {
+		helpers.HandleError(c, apperrors.Internal(err))
+		return
+	}
+
+	session.RefreshTokenHash = helpers.HashToken(refreshToken)
+	if err := helpers.CreateSession(ctx, session, uc.sessionCollection); err != nil {
+		helpers.HandleError(c, apperrors.Internal(err))
+		return
+	}
+
+	response := gin.H{"message": "Login successful", "token": token, "refresh_token": refreshToken, "session_id": session.ID.Hex()}
+	if mfaPending {
+		response["mfa_enrollment_required"] = true
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (uc *UserController) RefreshTokens() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		ctx, cancel := helpers.RequestContext(c)
 		defer cancel()
 
-		var loginUser, foundUser models.User
+		var req dto.RefreshRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			helpers.HandleError(c, apperrors.InvalidInput(err))
+			return
+		}
+
+		if err := uc.validate.Struct(&req); err != nil {
+			helpers.HandleError(c, apperrors.InvalidInput(err))
+			return
+		}
+
+		claims, err := uc.tokens.ValidateRefreshToken(req.RefreshToken)
+		if err != nil {
+			helpers.HandleError(c, apperrors.Unauthorized("invalid or expired refresh token").Wrap(err))
+			return
+		}
 
-		if err := c.BindJSON(&loginUser); err != nil {
-			helpers.HandleError(c, http.StatusBadRequest, fmt.Errorf("binding JSON: %w", err))
+		sessionID, err := bson.ObjectIDFromHex(claims.SessionID)
+		if err != nil {
+			helpers.HandleError(c, apperrors.Unauthorized("invalid session"))
 			return
 		}
 
-		err := uc.userCollection.FindOne(ctx, bson.M{"email": bson.M{"$regex": *loginUser.Email, "$options": "i"}}).Decode(&foundUser)
+		var user models.User
+		err = uc.userCollection.FindOne(ctx, bson.M{"user_id": claims.Uid}).Decode(&user)
 		if err != nil {
 			if errors.Is(err, mongo.ErrNoDocuments) {
-				helpers.HandleError(c, http.StatusUnauthorized, errors.New("invalid email or password"))
+				helpers.HandleError(c, apperrors.Unauthorized("invalid session"))
 			} else {
-				helpers.HandleError(c, http.StatusInternalServerError, fmt.Errorf("finding user: %w", err))
+				helpers.HandleError(c, apperrors.Internal(fmt.Errorf("finding user: %w", err)))
 			}
 			return
 		}
 
-		passwordMatch, err := helpers.ConfirmPassword(*foundUser.Password, *loginUser.Password)
-		if err != nil || !passwordMatch {
-			helpers.HandleError(c, http.StatusUnauthorized, errors.New("invalid email or password"))
+		mfaPending, err := uc.adminMFAPending(ctx, &user)
+		if err != nil {
+			helpers.HandleError(c, apperrors.Internal(err))
 			return
 		}
 
-		token, refreshToken, err := helpers.GenerateAllTokens(*foundUser.Email, *foundUser.Name, *foundUser.Username, *foundUser.UserType, foundUser.UserID)
+		token, refreshToken, err := uc.tokens.GenerateAllTokens(*user.Email, *user.Name, *user.Username, *user.UserType, user.UserID, claims.SessionID, mfaPending)
 		if err != nil {
-			helpers.HandleError(c, http.StatusInternalServerError, err)
+			helpers.HandleError(c, apperrors.Internal(err))
 			return
 		}
 
-		if err := helpers.UpdateTokens(token, refreshToken, foundUser.UserID, uc.userCollection); err != nil {
-			helpers.HandleError(c, http.StatusInternalServerError, err)
+		if err := helpers.RotateSessionRefreshToken(ctx, sessionID, user.UserID, req.RefreshToken, refreshToken, uc.tokens.RefreshTokenTTL(), uc.sessionCollection); err != nil {
+			helpers.HandleError(c, err)
 			return
 		}
 
-		c.JSON(http.StatusOK, gin.H{"message": "Login successful", "token": token, "refresh_token": refreshToken})
+		c.JSON(http.StatusOK, gin.H{"message": "Token refreshed successfully", "token": token, "refresh_token": refreshToken})
 	}
 }
 
@@ -145,12 +277,12 @@ func (uc *UserController) GetUser() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userId := c.Param("user_id")
 
-		if err := helpers.MatchUserID(c, userId); err != nil && c.GetString("user_type") != helpers.AdminRole {
-			helpers.HandleError(c, http.StatusUnauthorized, err)
+		if err := helpers.MatchUserID(c, userId); err != nil && helpers.VerifyUserType(c, helpers.AdminRole) != nil {
+			helpers.HandleError(c, err)
 			return
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		ctx, cancel := helpers.RequestContext(c)
 		defer cancel()
 
 		var user models.User
@@ -158,39 +290,35 @@ func (uc *UserController) GetUser() gin.HandlerFunc {
 
 		if err != nil {
 			if errors.Is(err, mongo.ErrNoDocuments) {
-				helpers.HandleError(c, http.StatusNotFound, errors.New("user not found"))
+				helpers.HandleError(c, apperrors.NotFound("user not found"))
 			} else {
-				helpers.HandleError(c, http.StatusInternalServerError, fmt.Errorf("finding user: %w", err))
+				helpers.HandleError(c, apperrors.Internal(fmt.Errorf("finding user: %w", err)))
 			}
 			return
 		}
 
-		user.Password = nil
-		user.Token = nil
-		user.RefreshToken = nil
-
-		c.JSON(http.StatusOK, user)
+		c.JSON(http.StatusOK, dto.NewUserResponse(&user))
 	}
 }
 
 func (uc *UserController) GetUsers() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if err := helpers.VerifyUserType(c, helpers.AdminRole); err != nil {
-			helpers.HandleError(c, http.StatusForbidden, err)
+			helpers.HandleError(c, err)
 			return
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		ctx, cancel := helpers.RequestContext(c)
 		defer cancel()
 
 		page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
 		if err != nil || page < 1 {
-			helpers.HandleError(c, http.StatusBadRequest, errors.New("invalid page number"))
+			helpers.HandleError(c, apperrors.Validation("invalid page number"))
 			return
 		}
 		limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
 		if err != nil || limit < 1 {
-			helpers.HandleError(c, http.StatusBadRequest, errors.New("invalid limit number"))
+			helpers.HandleError(c, apperrors.Validation("invalid limit number"))
 			return
 		}
 		skip := (page - 1) * limit
@@ -201,23 +329,17 @@ func (uc *UserController) GetUsers() gin.HandlerFunc {
 
 		cursor, err := uc.userCollection.Find(ctx, bson.M{}, findOptions)
 		if err != nil {
-			helpers.HandleError(c, http.StatusInternalServerError, fmt.Errorf("finding users: %w", err))
+			helpers.HandleError(c, apperrors.Internal(fmt.Errorf("finding users: %w", err)))
 			return
 		}
 		defer cursor.Close(ctx)
 
 		var users []models.User
 		if err := cursor.All(ctx, &users); err != nil {
-			helpers.HandleError(c, http.StatusInternalServerError, fmt.Errorf("decoding users: %w", err))
+			helpers.HandleError(c, apperrors.Internal(fmt.Errorf("decoding users: %w", err)))
 			return
 		}
 
-		for i := range users {
-			users[i].Password = nil
-			users[i].Token = nil
-			users[i].RefreshToken = nil
-		}
-
-		c.JSON(http.StatusOK, gin.H{"users": users})
+		c.JSON(http.StatusOK, gin.H{"users": dto.NewUserResponses(users)})
 	}
 }
@@ -0,0 +1,163 @@
+package database
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/mayurvarma14/go-movie-review/internals/config"
+	"github.com/mayurvarma14/go-movie-review/internals/metrics"
+	"github.com/mayurvarma14/go-movie-review/internals/tracing"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/v2/mongo/readpref"
+	"go.mongodb.org/mongo-driver/v2/mongo/writeconcern"
+)
+
+// buildURI assembles a connection string from the structured settings.
+// Credentials are escaped so passwords may contain characters like @ or /.
+// Host may list several comma-separated members of a replica set; members
+// without a port get cfg.Port.
+func buildURI(cfg config.MongoConfig) string {
+	scheme := "mongodb"
+	hosts := strings.Split(cfg.Host, ",")
+	for i, host := range hosts {
+		host = strings.TrimSpace(host)
+		if _, _, err := net.SplitHostPort(host); err != nil && !cfg.SRV {
+			host = net.JoinHostPort(host, strconv.Itoa(cfg.Port))
+		}
+		hosts[i] = host
+	}
+	if cfg.SRV {
+		scheme = "mongodb+srv"
+	}
+
+	u := url.URL{
+		Scheme: scheme,
+		Host:   strings.Join(hosts, ","),
+		Path:   "/" + cfg.Database,
+	}
+	if cfg.User != "" {
+		u.User = url.UserPassword(cfg.User, cfg.Password)
+	}
+
+	query := url.Values{}
+	if cfg.AuthSource != "" {
+		query.Set("authSource", cfg.AuthSource)
+	}
+	if cfg.ReplicaSet != "" {
+		query.Set("replicaSet", cfg.ReplicaSet)
+	}
+	u.RawQuery = query.Encode()
+
+	return u.String()
+}
+
+// databaseFromURI returns the default database named in the URI path, if any.
+func databaseFromURI(uri string) string {
+	_, rest, ok := strings.Cut(uri, "://")
+	if !ok {
+		return ""
+	}
+	rest, _, _ = strings.Cut(rest, "?")
+	if at := strings.LastIndex(rest, "@"); at >= 0 {
+		rest = rest[at+1:]
+	}
+	_, name, _ := strings.Cut(rest, "/")
+	name, err := url.PathUnescape(name)
+	if err != nil {
+		return ""
+	}
+	return name
+}
+
+func newClientOptions(uri string, cfg config.MongoConfig) (*options.ClientOptions, error) {
+	opts := options.Client().
+		ApplyURI(uri).
+		SetMonitor(chainMonitors(metrics.CommandMonitor(), tracing.CommandMonitor())).
+		SetConnectTimeout(cfg.ConnectTimeout).
+		SetTimeout(cfg.Timeout)
+
+	if cfg.MaxPoolSize > 0 {
+		opts.SetMaxPoolSize(uint64(cfg.MaxPoolSize))
+	}
+	if cfg.MinPoolSize > 0 {
+		opts.SetMinPoolSize(uint64(cfg.MinPoolSize))
+	}
+	if cfg.MaxConnIdleTime > 0 {
+		opts.SetMaxConnIdleTime(cfg.MaxConnIdleTime)
+	}
+
+	if cfg.ReadPreference != "" {
+		mode, err := readpref.ModeFromString(cfg.ReadPreference)
+		if err != nil {
+			return nil, err
+		}
+		pref, err := readpref.New(mode)
+		if err != nil {
+			return nil, fmt.Errorf("read preference: %w", err)
+		}
+		opts.SetReadPreference(pref)
+	}
+	if cfg.ReadConcern != "" {
+		opts.SetReadConcern(&readconcern.ReadConcern{Level: strings.ToLower(cfg.ReadConcern)})
+	}
+	if cfg.WriteConcern != "" {
+		var w any = cfg.WriteConcern
+		if n, err := strconv.Atoi(cfg.WriteConcern); err == nil {
+			w = n
+		}
+		opts.SetWriteConcern(&writeconcern.WriteConcern{W: w})
+	}
+
+	if cfg.TLS || cfg.TLSCAFile != "" || cfg.TLSCertKeyFile != "" {
+		tlsConfig, err := newTLSConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		opts.SetTLSConfig(tlsConfig)
+	}
+
+	if err := opts.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid MongoDB options: %w", err)
+	}
+	return opts, nil
+}
+
+func newTLSConfig(cfg config.MongoConfig) (*tls.Config, error) {
+	// TLSInsecure is an explicit opt-in for self-signed development clusters.
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: cfg.TLSInsecure,
+	}
+
+	if cfg.TLSCAFile != "" {
+		pem, err := os.ReadFile(cfg.TLSCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading TLS CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.New("TLS CA file contains no certificates")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.TLSCertKeyFile != "" {
+		// Like tlsCertificateKeyFile in a URI, the file holds both the client
+		// certificate and its private key.
+		cert, err := tls.LoadX509KeyPair(cfg.TLSCertKeyFile, cfg.TLSCertKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading TLS client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
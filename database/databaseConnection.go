@@ -2,12 +2,13 @@ package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"os"
-	"time"
+	"log/slog"
 
+	"github.com/mayurvarma14/go-movie-review/internals/config"
+	"go.mongodb.org/mongo-driver/v2/event"
 	"go.mongodb.org/mongo-driver/v2/mongo"
-	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
 type Database struct {
@@ -15,29 +16,75 @@ type Database struct {
 	Name   string
 }
 
-func New(ctx context.Context) (*Database, error) {
-	uri := fmt.Sprintf(
-		"mongodb://%s:%s@%s:27017/%s?authSource=%s",
-		os.Getenv("MONGO_APP_USER"),
-		os.Getenv("MONGO_APP_PASSWORD"),
-		os.Getenv("MONGO_DOMAIN"),
-		os.Getenv("MONGO_INITDB_DATABASE"),
-		os.Getenv("MONGO_AUTH_SOURCE"),
-	)
-
-	client, err := mongo.Connect(options.Client().SetTimeout(10 * time.Second).ApplyURI(uri))
+func New(ctx context.Context, cfg config.MongoConfig) (*Database, error) {
+	uri := cfg.URI
+	if uri == "" {
+		uri = buildURI(cfg)
+	}
+
+	name := cfg.Database
+	if name == "" {
+		name = databaseFromURI(uri)
+	}
+	if name == "" {
+		return nil, errors.New("no database name: set MONGO_INITDB_DATABASE or include it in MONGO_URI")
+	}
+
+	clientOptions, err := newClientOptions(uri, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := mongo.Connect(clientOptions)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect: %w", err)
 	}
 
-	fmt.Println("Connected to MongoDB Successfully!")
+	// Connect is lazy, so ping to fail fast on bad hosts, credentials or TLS
+	// settings instead of on the first request.
+	pingCtx, cancel := context.WithTimeout(ctx, cfg.ConnectTimeout)
+	defer cancel()
+	if err := client.Ping(pingCtx, nil); err != nil {
+		_ = client.Disconnect(context.Background())
+		return nil, fmt.Errorf("failed to ping: %w", err)
+	}
+
+	slog.InfoContext(ctx, "connected to MongoDB", "hosts", clientOptions.Hosts, "database", name)
 
 	return &Database{
 		Client: client,
-		Name:   os.Getenv("MONGO_INITDB_DATABASE"),
+		Name:   name,
 	}, nil
 }
 
 func (db *Database) OpenCollection(name string) *mongo.Collection {
 	return db.Client.Database(db.Name).Collection(name)
 }
+
+// chainMonitors fans command events out to several monitors, since the driver
+// accepts only one.
+func chainMonitors(monitors ...*event.CommandMonitor) *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Started: func(ctx context.Context, e *event.CommandStartedEvent) {
+			for _, m := range monitors {
+				if m.Started != nil {
+					m.Started(ctx, e)
+				}
+			}
+		},
+		Succeeded: func(ctx context.Context, e *event.CommandSucceededEvent) {
+			for _, m := range monitors {
+				if m.Succeeded != nil {
+					m.Succeeded(ctx, e)
+				}
+			}
+		},
+		Failed: func(ctx context.Context, e *event.CommandFailedEvent) {
+			for _, m := range monitors {
+				if m.Failed != nil {
+					m.Failed(ctx, e)
+				}
+			}
+		},
+	}
+}
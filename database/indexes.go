@@ -0,0 +1,168 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// trashIndex covers listing and purging the trash of a collection.
+var trashIndex = mongo.IndexModel{
+	Keys:    bson.D{{Key: "deleted_at", Value: -1}},
+	Options: options.Index().SetPartialFilterExpression(bson.M{"deleted_at": bson.M{"$exists": true}}),
+}
+
+// uniqueIDs are the public ids that no two live documents may share.
+// deleted_at is part of the key so trashed documents keep their ids without
+// blocking a live document from reusing them.
+var uniqueIDs = []struct{ collection, field string }{{"movie", "movie_id"}, {"genre", "genre_id"}}
+
+func uniqueIDIndex(field string) mongo.IndexModel {
+	return mongo.IndexModel{
+		Keys:    bson.D{{Key: field, Value: 1}, {Key: "deleted_at", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+}
+
+// reviewIndexes serve listing a movie's reviews and a user's reviews, and
+// counting reviews per movie.
+var reviewIndexes = []mongo.IndexModel{
+	{Keys: bson.D{{Key: "movie_id", Value: 1}, {Key: "deleted_at", Value: 1}}},
+	{Keys: bson.D{{Key: "reviewer_id", Value: 1}, {Key: "deleted_at", Value: 1}}},
+}
+
+// EnsureCatalogIndexes creates the movie, genre and review indexes that
+// migrations define if any are missing, such as after a collection was
+// restored from a dump without them. Existing indexes are left as they are.
+func (db *Database) EnsureCatalogIndexes(ctx context.Context) error {
+	for _, name := range TrashCollections {
+		if _, err := db.OpenCollection(name).Indexes().CreateOne(ctx, trashIndex); err != nil {
+			return fmt.Errorf("indexing %s trash: %w", name, err)
+		}
+	}
+	for _, key := range uniqueIDs {
+		collection := db.OpenCollection(key.collection)
+		if err := checkUnique(ctx, collection, key.field); err != nil {
+			return err
+		}
+		if _, err := collection.Indexes().CreateOne(ctx, uniqueIDIndex(key.field)); err != nil {
+			return fmt.Errorf("indexing %s %s: %w", key.collection, key.field, err)
+		}
+	}
+	if _, err := db.OpenCollection("review").Indexes().CreateMany(ctx, reviewIndexes); err != nil {
+		return fmt.Errorf("indexing reviews: %w", err)
+	}
+	return nil
+}
+
+// checkUnique names the values of field that more than one live document
+// shares, which would otherwise fail a unique index build with only the
+// first of them.
+func checkUnique(ctx context.Context, collection *mongo.Collection, field string) error {
+	cursor, err := collection.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"deleted_at": nil}}},
+		{{Key: "$group", Value: bson.M{"_id": "$" + field, "count": bson.M{"$sum": 1}}}},
+		{{Key: "$match", Value: bson.M{"count": bson.M{"$gt": 1}}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("finding duplicate %s values: %w", field, err)
+	}
+	var duplicates []struct {
+		Value any `bson:"_id"`
+		Count int `bson:"count"`
+	}
+	if err := cursor.All(ctx, &duplicates); err != nil {
+		return fmt.Errorf("finding duplicate %s values: %w", field, err)
+	}
+	if len(duplicates) == 0 {
+		return nil
+	}
+
+	values := make([]string, len(duplicates))
+	for i, d := range duplicates {
+		values[i] = fmt.Sprintf("%v (%d documents)", d.Value, d.Count)
+	}
+	return fmt.Errorf("%s %s is shared by live documents, trash or renumber them and migrate again: %s",
+		collection.Name(), field, strings.Join(values, ", "))
+}
+
+const recountBatchSize = 500
+
+// RecountReviews sets each movie's review_count to the number of its reviews
+// outside the trash and returns how many movies it corrected. The count is
+// derived, so like the review handlers that keep it up to date, a
+// correction leaves the movie's version alone.
+func (db *Database) RecountReviews(ctx context.Context) (int64, error) {
+	cursor, err := db.OpenCollection("review").Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"deleted_at": nil}}},
+		{{Key: "$group", Value: bson.M{"_id": "$movie_id", "count": bson.M{"$sum": 1}}}},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("counting reviews: %w", err)
+	}
+	var counted []struct {
+		MovieID int   `bson:"_id"`
+		Count   int64 `bson:"count"`
+	}
+	if err := cursor.All(ctx, &counted); err != nil {
+		return 0, fmt.Errorf("counting reviews: %w", err)
+	}
+	counts := make(map[int]int64, len(counted))
+	for _, c := range counted {
+		counts[c.MovieID] = c.Count
+	}
+
+	movies := db.OpenCollection("movie")
+	cursor, err = movies.Find(ctx, bson.M{}, options.Find().SetProjection(bson.M{"movie_id": 1, "review_count": 1}))
+	if err != nil {
+		return 0, fmt.Errorf("finding movies: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var corrected int64
+	var writes []mongo.WriteModel
+	flush := func() error {
+		if len(writes) == 0 {
+			return nil
+		}
+		result, err := movies.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+		if err != nil {
+			return fmt.Errorf("updating review counts: %w", err)
+		}
+		corrected += result.ModifiedCount
+		writes = writes[:0]
+		return nil
+	}
+
+	for cursor.Next(ctx) {
+		var movie struct {
+			ID          bson.ObjectID `bson:"_id"`
+			MovieID     int           `bson:"movie_id"`
+			ReviewCount *int64        `bson:"review_count"`
+		}
+		if err := cursor.Decode(&movie); err != nil {
+			return corrected, fmt.Errorf("decoding movie: %w", err)
+		}
+		count := counts[movie.MovieID]
+		if movie.ReviewCount != nil && *movie.ReviewCount == count {
+			continue
+		}
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": movie.ID}).
+			SetUpdate(bson.M{"$set": bson.M{"review_count": count}}))
+		if len(writes) == recountBatchSize {
+			if err := flush(); err != nil {
+				return corrected, err
+			}
+		}
+	}
+	if err := cursor.Err(); err != nil {
+		return corrected, fmt.Errorf("reading movies: %w", err)
+	}
+	return corrected, flush()
+}
@@ -0,0 +1,210 @@
+package database
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/event"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/x/mongo/driver/drivertest"
+)
+
+// recorder keeps the commands a mock deployment was sent, in order.
+type recorder struct {
+	mu       sync.Mutex
+	commands []bson.Raw
+}
+
+func (r *recorder) started(_ context.Context, e *event.CommandStartedEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.commands = append(r.commands, append(bson.Raw(nil), e.Command...))
+}
+
+// named returns the recorded commands whose name is name.
+func (r *recorder) named(name string) []bson.Raw {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var found []bson.Raw
+	for _, cmd := range r.commands {
+		if elem, err := cmd.IndexErr(0); err == nil && elem.Key() == name {
+			found = append(found, cmd)
+		}
+	}
+	return found
+}
+
+// newMockDatabase returns a Database whose server replies with responses in
+// order, and a recorder of the commands it was sent.
+func newMockDatabase(t *testing.T, responses ...bson.D) (*Database, *recorder) {
+	t.Helper()
+	md := drivertest.NewMockDeployment(responses...)
+	rec := &recorder{}
+	opts := options.Client().SetMonitor(&event.CommandMonitor{Started: rec.started})
+	opts.Deployment = md
+	client, err := mongo.Connect(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = client.Disconnect(context.Background()) })
+	return &Database{Client: client, Name: "test"}, rec
+}
+
+func cursorReply(ns string, docs ...bson.D) bson.D {
+	batch := bson.A{}
+	for _, d := range docs {
+		batch = append(batch, d)
+	}
+	return bson.D{
+		{Key: "ok", Value: 1},
+		{Key: "cursor", Value: bson.D{
+			{Key: "id", Value: int64(0)},
+			{Key: "ns", Value: ns},
+			{Key: "firstBatch", Value: batch},
+		}},
+	}
+}
+
+// recountReplies scripts RecountReviews against three movies: 1 has two live
+// reviews but a stale count, 2 is already right, and 3 has a count but no
+// live reviews left.
+func recountReplies() []bson.D {
+	return []bson.D{
+		cursorReply("test.review",
+			bson.D{{Key: "_id", Value: 1}, {Key: "count", Value: int64(2)}},
+			bson.D{{Key: "_id", Value: 2}, {Key: "count", Value: int64(1)}},
+		),
+		cursorReply("test.movie",
+			bson.D{{Key: "_id", Value: bson.NewObjectID()}, {Key: "movie_id", Value: 1}, {Key: "review_count", Value: int64(5)}},
+			bson.D{{Key: "_id", Value: bson.NewObjectID()}, {Key: "movie_id", Value: 2}, {Key: "review_count", Value: int64(1)}},
+			bson.D{{Key: "_id", Value: bson.NewObjectID()}, {Key: "movie_id", Value: 3}, {Key: "review_count", Value: int64(4)}},
+		),
+		{{Key: "ok", Value: 1}, {Key: "n", Value: 2}, {Key: "nModified", Value: 2}},
+	}
+}
+
+// checkRecount asserts that the reviews were counted outside the trash and
+// that only movies 1 and 3 were corrected, to 2 and 0.
+func checkRecount(t *testing.T, rec *recorder) {
+	t.Helper()
+
+	aggregates := rec.named("aggregate")
+	if len(aggregates) != 1 {
+		t.Fatalf("got %d aggregate commands, want 1", len(aggregates))
+	}
+	var aggregate struct {
+		Pipeline []struct {
+			Match map[string]any `bson:"$match"`
+		} `bson:"pipeline"`
+	}
+	if err := bson.Unmarshal(aggregates[0], &aggregate); err != nil {
+		t.Fatal(err)
+	}
+	match := aggregate.Pipeline[0].Match
+	if deleted, ok := match["deleted_at"]; !ok || deleted != nil || len(match) != 1 {
+		t.Errorf("first stage matches %v, want only deleted_at: null", match)
+	}
+
+	updates := rec.named("update")
+	if len(updates) != 1 {
+		t.Fatalf("got %d update commands, want 1", len(updates))
+	}
+	var update struct {
+		Updates []struct {
+			U map[string]map[string]int64 `bson:"u"`
+		} `bson:"updates"`
+	}
+	if err := bson.Unmarshal(updates[0], &update); err != nil {
+		t.Fatal(err)
+	}
+	var got []int64
+	for _, u := range update.Updates {
+		set := u.U["$set"]
+		if len(u.U) != 1 || len(set) != 1 {
+			t.Errorf("update %v should only set review_count", u.U)
+		}
+		got = append(got, set["review_count"])
+	}
+	if len(got) != 2 || got[0] != 2 || got[1] != 0 {
+		t.Errorf("review counts set = %v, want [2 0]", got)
+	}
+}
+
+func TestRecountReviews(t *testing.T) {
+	db, rec := newMockDatabase(t, recountReplies()...)
+
+	corrected, err := db.RecountReviews(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if corrected != 2 {
+		t.Errorf("corrected = %d, want 2", corrected)
+	}
+	checkRecount(t, rec)
+}
+
+func TestRecountReviewsSkipsWriteWhenCurrent(t *testing.T) {
+	db, rec := newMockDatabase(t,
+		cursorReply("test.review", bson.D{{Key: "_id", Value: 1}, {Key: "count", Value: int64(1)}}),
+		cursorReply("test.movie",
+			bson.D{{Key: "_id", Value: bson.NewObjectID()}, {Key: "movie_id", Value: 1}, {Key: "review_count", Value: int64(1)}},
+		),
+	)
+
+	corrected, err := db.RecountReviews(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if corrected != 0 {
+		t.Errorf("corrected = %d, want 0", corrected)
+	}
+	if updates := rec.named("update"); len(updates) != 0 {
+		t.Errorf("got %d update commands, want none", len(updates))
+	}
+}
+
+func TestMigration10(t *testing.T) {
+	var m *Migration
+	for i := range migrations {
+		if migrations[i].Version == 10 {
+			m = &migrations[i]
+		}
+	}
+	if m == nil {
+		t.Fatal("no migration 10")
+	}
+
+	replies := append([]bson.D{{{Key: "ok", Value: 1}}}, recountReplies()...)
+	db, rec := newMockDatabase(t, replies...)
+	if err := m.Up(context.Background(), db); err != nil {
+		t.Fatal(err)
+	}
+
+	creates := rec.named("createIndexes")
+	if len(creates) != 1 {
+		t.Fatalf("got %d createIndexes commands, want 1", len(creates))
+	}
+	var create struct {
+		Collection string `bson:"createIndexes"`
+		Indexes    []struct {
+			Key bson.D `bson:"key"`
+		} `bson:"indexes"`
+	}
+	if err := bson.Unmarshal(creates[0], &create); err != nil {
+		t.Fatal(err)
+	}
+	if create.Collection != "review" {
+		t.Errorf("indexed %q, want review", create.Collection)
+	}
+	var leading []string
+	for _, idx := range create.Indexes {
+		leading = append(leading, idx.Key[0].Key)
+	}
+	if len(leading) != 2 || leading[0] != "movie_id" || leading[1] != "reviewer_id" {
+		t.Errorf("indexes lead with %v, want [movie_id reviewer_id]", leading)
+	}
+	checkRecount(t, rec)
+}
@@ -0,0 +1,222 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type Migration struct {
+	Version     int
+	Description string
+	Up          func(ctx context.Context, db *Database) error
+}
+
+type migrationRecord struct {
+	Version     int       `bson:"_id"`
+	Description string    `bson:"description"`
+	AppliedAt   time.Time `bson:"applied_at"`
+}
+
+var migrations = []Migration{
+	{
+		Version:     1,
+		Description: "remove plaintext tokens from user documents",
+		Up: func(ctx context.Context, db *Database) error {
+			_, err := db.OpenCollection("user").UpdateMany(ctx,
+				bson.M{"$or": bson.A{
+					bson.M{"token": bson.M{"$exists": true}},
+					bson.M{"refresh_token": bson.M{"$exists": true}},
+				}},
+				bson.M{"$unset": bson.M{"token": "", "refresh_token": ""}},
+			)
+			return err
+		},
+	},
+	{
+		Version:     2,
+		Description: "index sessions by user and expire them automatically",
+		Up: func(ctx context.Context, db *Database) error {
+			_, err := db.OpenCollection("session").Indexes().CreateMany(ctx, []mongo.IndexModel{
+				{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "last_seen_at", Value: -1}}},
+				{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+			})
+			return err
+		},
+	},
+	{
+		Version:     3,
+		Description: "expire idle rate limit buckets",
+		Up: func(ctx context.Context, db *Database) error {
+			_, err := db.OpenCollection("rate_limit").Indexes().CreateOne(ctx, mongo.IndexModel{
+				Keys:    bson.D{{Key: "expires_at", Value: 1}},
+				Options: options.Index().SetExpireAfterSeconds(0),
+			})
+			return err
+		},
+	},
+	{
+		// Untagged model fields used to be stored under lowercased Go names,
+		// so movie URLs set on create lived in movieurl while updates wrote
+		// movie_url.
+		Version:     4,
+		Description: "store movie URLs and user types under snake_case keys",
+		Up: func(ctx context.Context, db *Database) error {
+			renames := []struct{ collection, from, to string }{
+				{"movie", "movieurl", "movie_url"},
+				{"user", "usertype", "user_type"},
+			}
+			for _, r := range renames {
+				collection := db.OpenCollection(r.collection)
+				if _, err := collection.UpdateMany(ctx,
+					bson.M{r.from: bson.M{"$exists": true}, r.to: bson.M{"$exists": false}},
+					bson.M{"$rename": bson.M{r.from: r.to}},
+				); err != nil {
+					return err
+				}
+				if _, err := collection.UpdateMany(ctx,
+					bson.M{r.from: bson.M{"$exists": true}},
+					bson.M{"$unset": bson.M{r.from: ""}},
+				); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		Version:     5,
+		Description: "start versioning movies, genres and reviews",
+		Up: func(ctx context.Context, db *Database) error {
+			for _, name := range []string{"movie", "genre", "review"} {
+				if _, err := db.OpenCollection(name).UpdateMany(ctx,
+					bson.M{"version": bson.M{"$exists": false}},
+					bson.M{"$set": bson.M{"version": 1}},
+				); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		Version:     6,
+		Description: "index soft-deleted documents for the trash and its purge",
+		Up: func(ctx context.Context, db *Database) error {
+			for _, name := range TrashCollections {
+				if _, err := db.OpenCollection(name).Indexes().CreateOne(ctx, trashIndex); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		Version:     7,
+		Description: "index the audit log by time, actor and resource",
+		Up: func(ctx context.Context, db *Database) error {
+			_, err := db.OpenCollection("audit").Indexes().CreateMany(ctx, []mongo.IndexModel{
+				{Keys: bson.D{{Key: "created_at", Value: -1}}},
+				{Keys: bson.D{{Key: "actor_id", Value: 1}, {Key: "created_at", Value: -1}}},
+				{Keys: bson.D{{Key: "resource", Value: 1}, {Key: "resource_id", Value: 1}, {Key: "created_at", Value: -1}}},
+			})
+			return err
+		},
+	},
+	{
+		Version:     8,
+		Description: "expire MFA challenge attempt counters with their tokens",
+		Up: func(ctx context.Context, db *Database) error {
+			_, err := db.OpenCollection("mfa_challenge").Indexes().CreateOne(ctx, mongo.IndexModel{
+				Keys:    bson.D{{Key: "expires_at", Value: 1}},
+				Options: options.Index().SetExpireAfterSeconds(0),
+			})
+			return err
+		},
+	},
+	{
+		Version:     9,
+		Description: "make movie_id and genre_id unique among live documents",
+		Up: func(ctx context.Context, db *Database) error {
+			for _, key := range uniqueIDs {
+				collection := db.OpenCollection(key.collection)
+				if err := checkUnique(ctx, collection, key.field); err != nil {
+					return err
+				}
+				if _, err := collection.Indexes().CreateOne(ctx, uniqueIDIndex(key.field)); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		Version:     10,
+		Description: "index reviews by movie and reviewer and count each movie's reviews",
+		Up: func(ctx context.Context, db *Database) error {
+			if _, err := db.OpenCollection("review").Indexes().CreateMany(ctx, reviewIndexes); err != nil {
+				return err
+			}
+			_, err := db.RecountReviews(ctx)
+			return err
+		},
+	},
+}
+
+func (db *Database) Migrate(ctx context.Context) error {
+	collection := db.OpenCollection("migration")
+
+	for _, m := range migrations {
+		err := collection.FindOne(ctx, bson.M{"_id": m.Version}).Err()
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			return fmt.Errorf("checking migration %d: %w", m.Version, err)
+		}
+
+		slog.InfoContext(ctx, "applying migration", "version", m.Version, "description", m.Description)
+		if err := m.Up(ctx, db); err != nil {
+			return fmt.Errorf("applying migration %d: %w", m.Version, err)
+		}
+
+		record := migrationRecord{Version: m.Version, Description: m.Description, AppliedAt: time.Now()}
+		if _, err := collection.InsertOne(ctx, record); err != nil {
+			return fmt.Errorf("recording migration %d: %w", m.Version, err)
+		}
+	}
+
+	return nil
+}
+
+func (db *Database) PendingMigrations(ctx context.Context) ([]int, error) {
+	cursor, err := db.OpenCollection("migration").Find(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("finding migrations: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var records []migrationRecord
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, fmt.Errorf("decoding migrations: %w", err)
+	}
+
+	applied := make(map[int]bool, len(records))
+	for _, r := range records {
+		applied[r.Version] = true
+	}
+
+	var pending []int
+	for _, m := range migrations {
+		if !applied[m.Version] {
+			pending = append(pending, m.Version)
+		}
+	}
+	return pending, nil
+}
@@ -0,0 +1,105 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// TrashCollections hold documents that are soft deleted: marked with
+// deleted_at and deleted_by instead of being removed.
+var TrashCollections = []string{"movie", "genre", "review"}
+
+const purgeBatchSize = 500
+
+// PurgeAuditor records documents from collection that are about to be
+// purged. A batch is only deleted once its auditor returns nil.
+type PurgeAuditor func(ctx context.Context, collection string, docs []bson.Raw) error
+
+// PurgeTrash permanently removes documents deleted before cutoff, in batches
+// that are each passed to audit first, and returns how many were removed per
+// collection.
+func (db *Database) PurgeTrash(ctx context.Context, cutoff time.Time, audit PurgeAuditor) (map[string]int64, error) {
+	purged := make(map[string]int64, len(TrashCollections))
+	for _, name := range TrashCollections {
+		count, err := db.purgeCollection(ctx, name, cutoff, audit)
+		purged[name] = count
+		if err != nil {
+			return purged, fmt.Errorf("purging %s: %w", name, err)
+		}
+	}
+	return purged, nil
+}
+
+func (db *Database) purgeCollection(ctx context.Context, name string, cutoff time.Time, audit PurgeAuditor) (int64, error) {
+	collection := db.OpenCollection(name)
+	filter := bson.M{"deleted_at": bson.M{"$lt": cutoff}}
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(purgeBatchSize)
+
+	var purged int64
+	for {
+		cursor, err := collection.Find(ctx, filter, opts)
+		if err != nil {
+			return purged, err
+		}
+		var docs []bson.Raw
+		if err := cursor.All(ctx, &docs); err != nil {
+			return purged, err
+		}
+		if len(docs) == 0 {
+			return purged, nil
+		}
+
+		if err := audit(ctx, name, docs); err != nil {
+			return purged, fmt.Errorf("auditing purge: %w", err)
+		}
+
+		ids := make([]bson.RawValue, len(docs))
+		for i, doc := range docs {
+			ids[i] = doc.Lookup("_id")
+		}
+		// Keeping the cutoff in the filter leaves alone anything restored
+		// since the batch was read.
+		result, err := collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}, "deleted_at": bson.M{"$lt": cutoff}})
+		if err != nil {
+			return purged, err
+		}
+		purged += result.DeletedCount
+
+		if len(docs) < purgeBatchSize {
+			return purged, nil
+		}
+	}
+}
+
+// RunTrashPurger purges documents older than retention every interval until
+// ctx is done. Zero retention keeps the trash forever.
+func (db *Database) RunTrashPurger(ctx context.Context, retention, interval time.Duration, audit PurgeAuditor) {
+	if retention <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		purged, err := db.PurgeTrash(ctx, time.Now().Add(-retention), audit)
+		if err != nil {
+			slog.ErrorContext(ctx, "purging trash failed", "error", err)
+		}
+		for name, count := range purged {
+			if count > 0 {
+				slog.InfoContext(ctx, "purged trash", "collection", name, "count", count)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,116 @@
+// Package docs serves the OpenAPI description of the API and a Swagger UI
+// for browsing it, both embedded in the binary.
+package docs
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files/v2"
+)
+
+//go:embed openapi.json
+var spec []byte
+
+//go:embed swagger-initializer.js
+var swaggerInitializer []byte
+
+// uiPolicy loosens the API's default content security policy just enough for
+// the Swagger UI assets, which are all served from this origin. The UI uses
+// inline styles and data: URIs for its icons.
+const uiPolicy = "default-src 'self'; style-src 'self' 'unsafe-inline'; img-src 'self' data:; frame-ancestors 'none'"
+
+var operationMethods = map[string]bool{
+	http.MethodGet: true, http.MethodPost: true, http.MethodPut: true, http.MethodPatch: true,
+	http.MethodDelete: true, http.MethodHead: true, http.MethodOptions: true,
+}
+
+func Spec() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json", spec)
+	}
+}
+
+// UI serves the Swagger UI. It must be mounted on a route ending in
+// *filepath.
+func UI() gin.HandlerFunc {
+	files := http.FileServer(http.FS(swaggerFiles.FS))
+	// Served directly because http.FileServer redirects index.html to ./.
+	index, err := fs.ReadFile(swaggerFiles.FS, "index.html")
+	if err != nil {
+		panic(fmt.Sprintf("docs: reading embedded Swagger UI: %v", err))
+	}
+
+	return func(c *gin.Context) {
+		c.Header("Content-Security-Policy", uiPolicy)
+
+		switch file := strings.TrimPrefix(c.Param("filepath"), "/"); file {
+		case "", "index.html":
+			c.Data(http.StatusOK, "text/html; charset=utf-8", index)
+		case "swagger-initializer.js":
+			c.Data(http.StatusOK, "application/javascript", swaggerInitializer)
+		default:
+			c.Request.URL.Path = "/" + file
+			files.ServeHTTP(c.Writer, c.Request)
+		}
+	}
+}
+
+// CheckCoverage compares the registered routes with the document and reports
+// every route that is undocumented and every documented operation that no
+// longer exists. Catch-all routes, such as the UI's, are skipped.
+func CheckCoverage(routes gin.RoutesInfo) error {
+	var doc struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		return fmt.Errorf("parsing OpenAPI document: %w", err)
+	}
+
+	documented := make(map[string]bool)
+	for path, item := range doc.Paths {
+		for method := range item {
+			if method = strings.ToUpper(method); operationMethods[method] {
+				documented[method+" "+path] = true
+			}
+		}
+	}
+
+	var problems []string
+	for _, route := range routes {
+		if strings.Contains(route.Path, "*") {
+			continue
+		}
+		key := route.Method + " " + openAPIPath(route.Path)
+		if !documented[key] {
+			problems = append(problems, "undocumented route "+key)
+		}
+		delete(documented, key)
+	}
+	for key := range documented {
+		problems = append(problems, "documented operation has no route: "+key)
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("OpenAPI document is out of date:\n  - %s", strings.Join(problems, "\n  - "))
+	}
+	return nil
+}
+
+// openAPIPath turns gin's /movies/:movie_id into /movies/{movie_id}.
+func openAPIPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if name, ok := strings.CutPrefix(segment, ":"); ok {
+			segments[i] = "{" + name + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
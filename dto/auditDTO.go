@@ -0,0 +1,51 @@
+package dto
+
+import (
+	"github.com/mayurvarma14/go-movie-review/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// AuditEntryResponse shows snapshots as the documents were stored, so their
+// keys follow the database rather than the resource responses.
+type AuditEntryResponse struct {
+	AuditID    string `json:"audit_id"`
+	ActorID    string `json:"actor_id"`
+	Action     string `json:"action"`
+	Resource   string `json:"resource"`
+	ResourceID string `json:"resource_id"`
+	Before     bson.M `json:"before,omitempty"`
+	After      bson.M `json:"after,omitempty"`
+	IP         string `json:"ip"`
+	RequestID  string `json:"request_id"`
+	CreatedAt  string `json:"created_at"`
+}
+
+func NewAuditEntryResponses(entries []models.AuditEntry) []AuditEntryResponse {
+	responses := make([]AuditEntryResponse, 0, len(entries))
+	for _, entry := range entries {
+		responses = append(responses, AuditEntryResponse{
+			AuditID:    entry.ID.Hex(),
+			ActorID:    entry.ActorID,
+			Action:     entry.Action,
+			Resource:   entry.Resource,
+			ResourceID: entry.ResourceID,
+			Before:     document(entry.Before),
+			After:      document(entry.After),
+			IP:         entry.IP,
+			RequestID:  entry.RequestID,
+			CreatedAt:  timestamp(entry.CreatedAt),
+		})
+	}
+	return responses
+}
+
+func document(raw bson.Raw) bson.M {
+	if raw == nil {
+		return nil
+	}
+	var doc bson.M
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		return nil
+	}
+	return doc
+}
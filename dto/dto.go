@@ -0,0 +1,26 @@
+// Package dto holds the shapes in which resources enter and leave the API.
+// Handlers bind requests into these types and map storage models onto
+// responses, so models can change without changing the wire format.
+package dto
+
+import "time"
+
+// timestamp renders times as RFC 3339 in UTC, the format every response uses.
+func timestamp(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// optionalTimestamp renders nil as an empty string, which omitempty drops.
+func optionalTimestamp(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return timestamp(*t)
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
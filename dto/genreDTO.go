@@ -0,0 +1,59 @@
+package dto
+
+import "github.com/mayurvarma14/go-movie-review/models"
+
+// GenreRequest is the body of POST and PUT /genres and the document PATCH
+// merges into. On PUT the genre_id in the path wins.
+type GenreRequest struct {
+	GenreID int    `json:"genre_id" validate:"required"`
+	Name    string `json:"name" validate:"required,min=4,max=100"`
+}
+
+func (r *GenreRequest) Model() models.Genre {
+	return models.Genre{
+		GenreID: r.GenreID,
+		Name:    &r.Name,
+	}
+}
+
+// NewGenreRequest is the inverse of Model, the starting point for a merge
+// patch.
+func NewGenreRequest(genre *models.Genre) GenreRequest {
+	return GenreRequest{
+		GenreID: genre.GenreID,
+		Name:    deref(genre.Name),
+	}
+}
+
+// GenreExportColumns orders the CSV columns of a genre export.
+var GenreExportColumns = []string{"genre_id", "name", "version", "created_at", "updated_at"}
+
+type GenreResponse struct {
+	GenreID   int    `json:"genre_id"`
+	Name      string `json:"name"`
+	Version   int64  `json:"version"`
+	CreatedAt string `json:"created_at"`
+	UpdatedAt string `json:"updated_at"`
+	DeletedAt string `json:"deleted_at,omitempty"`
+	DeletedBy string `json:"deleted_by,omitempty"`
+}
+
+func NewGenreResponse(genre *models.Genre) GenreResponse {
+	return GenreResponse{
+		GenreID:   genre.GenreID,
+		Name:      deref(genre.Name),
+		Version:   genre.Version,
+		CreatedAt: timestamp(genre.CreatedAt),
+		UpdatedAt: timestamp(genre.UpdatedAt),
+		DeletedAt: optionalTimestamp(genre.DeletedAt),
+		DeletedBy: genre.DeletedBy,
+	}
+}
+
+func NewGenreResponses(genres []models.Genre) []GenreResponse {
+	responses := make([]GenreResponse, 0, len(genres))
+	for i := range genres {
+		responses = append(responses, NewGenreResponse(&genres[i]))
+	}
+	return responses
+}
@@ -0,0 +1,98 @@
+package dto
+
+import (
+	"fmt"
+	"strconv"
+)
+
+const (
+	ImportCreated = "created"
+	ImportUpdated = "updated"
+	ImportSkipped = "skipped"
+	ImportFailed  = "failed"
+)
+
+// MovieImportRow is one movie in POST /movies/import. Genre names the genre
+// when genre_id is left out; when both are given they must agree.
+type MovieImportRow struct {
+	MovieRequest
+	Genre string `json:"genre"`
+}
+
+var (
+	MovieImportColumns = []string{"movie_id", "name", "topic", "genre_id", "genre", "movie_url"}
+	GenreImportColumns = []string{"genre_id", "name"}
+)
+
+func NewMovieImportRow(record map[string]string) (MovieImportRow, error) {
+	row := MovieImportRow{
+		MovieRequest: MovieRequest{
+			Name:     record["name"],
+			Topic:    record["topic"],
+			MovieURL: record["movie_url"],
+		},
+		Genre: record["genre"],
+	}
+	var err error
+	if row.MovieID, err = csvInt(record, "movie_id"); err != nil {
+		return row, err
+	}
+	row.GenreID, err = csvInt(record, "genre_id")
+	return row, err
+}
+
+func NewGenreImportRow(record map[string]string) (GenreRequest, error) {
+	row := GenreRequest{Name: record["name"]}
+	var err error
+	row.GenreID, err = csvInt(record, "genre_id")
+	return row, err
+}
+
+// csvInt reads an integer column, treating a missing or empty cell as zero
+// like an absent JSON field.
+func csvInt(record map[string]string, column string) (int, error) {
+	if record[column] == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(record[column])
+	if err != nil {
+		return 0, fmt.Errorf("%s must be an integer", column)
+	}
+	return n, nil
+}
+
+// ImportRowResult reports what happened to one row, identified by the line
+// it starts on in the upload.
+type ImportRowResult struct {
+	Line    int    `json:"line"`
+	Status  string `json:"status"`
+	MovieID int    `json:"movie_id,omitempty"`
+	GenreID int    `json:"genre_id,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+type ImportReport struct {
+	DryRun  bool              `json:"dry_run"`
+	Created int               `json:"created"`
+	Updated int               `json:"updated"`
+	Skipped int               `json:"skipped"`
+	Failed  int               `json:"failed"`
+	Rows    []ImportRowResult `json:"rows"`
+}
+
+func NewImportReport(dryRun bool, rows []ImportRowResult) ImportReport {
+	report := ImportReport{DryRun: dryRun, Rows: rows}
+	for _, row := range rows {
+		switch row.Status {
+		case ImportCreated:
+			report.Created++
+		case ImportUpdated:
+			report.Updated++
+		case ImportSkipped:
+			report.Skipped++
+		case ImportFailed:
+			report.Failed++
+		}
+	}
+	return report
+}
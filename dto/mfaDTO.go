@@ -0,0 +1,30 @@
+package dto
+
+import "github.com/mayurvarma14/go-movie-review/models"
+
+type MFACodeRequest struct {
+	Code string `json:"code" validate:"required,len=6,numeric"`
+}
+
+type MFALoginRequest struct {
+	MFAToken     string `json:"mfa_token" validate:"required"`
+	Code         string `json:"code" validate:"required_without=RecoveryCode"`
+	RecoveryCode string `json:"recovery_code" validate:"required_without=Code"`
+	DeviceLabel  string `json:"device_label" validate:"max=100"`
+}
+
+type MFAPolicyRequest struct {
+	RequireAdminMFA *bool `json:"require_admin_mfa" validate:"required"`
+}
+
+type SecuritySettingsResponse struct {
+	RequireAdminMFA bool   `json:"require_admin_mfa"`
+	UpdatedAt       string `json:"updated_at"`
+}
+
+func NewSecuritySettingsResponse(settings *models.SecuritySettings) SecuritySettingsResponse {
+	return SecuritySettingsResponse{
+		RequireAdminMFA: settings.RequireAdminMFA,
+		UpdatedAt:       timestamp(settings.UpdatedAt),
+	}
+}
@@ -0,0 +1,76 @@
+package dto
+
+import "github.com/mayurvarma14/go-movie-review/models"
+
+// MovieRequest is the body of POST and PUT /movies and the document PATCH
+// merges into. On PUT the movie_id in the path wins.
+type MovieRequest struct {
+	MovieID  int    `json:"movie_id"`
+	Name     string `json:"name" validate:"required"`
+	Topic    string `json:"topic" validate:"required"`
+	GenreID  int    `json:"genre_id"`
+	MovieURL string `json:"movie_url" validate:"required"`
+}
+
+func (r *MovieRequest) Model() models.Movie {
+	return models.Movie{
+		MovieID:  r.MovieID,
+		Name:     &r.Name,
+		Topic:    &r.Topic,
+		GenreID:  r.GenreID,
+		MovieURL: &r.MovieURL,
+	}
+}
+
+// NewMovieRequest is the inverse of Model, the starting point for a merge
+// patch.
+func NewMovieRequest(movie *models.Movie) MovieRequest {
+	return MovieRequest{
+		MovieID:  movie.MovieID,
+		Name:     deref(movie.Name),
+		Topic:    deref(movie.Topic),
+		GenreID:  movie.GenreID,
+		MovieURL: deref(movie.MovieURL),
+	}
+}
+
+// MovieExportColumns orders the CSV columns of a movie export.
+var MovieExportColumns = []string{"movie_id", "name", "topic", "genre_id", "movie_url", "review_count", "version", "created_at", "updated_at"}
+
+type MovieResponse struct {
+	MovieID     int    `json:"movie_id"`
+	Name        string `json:"name"`
+	Topic       string `json:"topic"`
+	GenreID     int    `json:"genre_id"`
+	MovieURL    string `json:"movie_url"`
+	ReviewCount int64  `json:"review_count"`
+	Version     int64  `json:"version"`
+	CreatedAt   string `json:"created_at"`
+	UpdatedAt   string `json:"updated_at"`
+	DeletedAt   string `json:"deleted_at,omitempty"`
+	DeletedBy   string `json:"deleted_by,omitempty"`
+}
+
+func NewMovieResponse(movie *models.Movie) MovieResponse {
+	return MovieResponse{
+		MovieID:     movie.MovieID,
+		Name:        deref(movie.Name),
+		Topic:       deref(movie.Topic),
+		GenreID:     movie.GenreID,
+		MovieURL:    deref(movie.MovieURL),
+		ReviewCount: movie.ReviewCount,
+		Version:     movie.Version,
+		CreatedAt:   timestamp(movie.CreatedAt),
+		UpdatedAt:   timestamp(movie.UpdatedAt),
+		DeletedAt:   optionalTimestamp(movie.DeletedAt),
+		DeletedBy:   movie.DeletedBy,
+	}
+}
+
+func NewMovieResponses(movies []models.Movie) []MovieResponse {
+	responses := make([]MovieResponse, 0, len(movies))
+	for i := range movies {
+		responses = append(responses, NewMovieResponse(&movies[i]))
+	}
+	return responses
+}
@@ -0,0 +1,54 @@
+package dto
+
+import "github.com/mayurvarma14/go-movie-review/models"
+
+// ReviewRequest is the body of POST /reviews. The reviewer is always the
+// caller.
+type ReviewRequest struct {
+	MovieID int    `json:"movie_id" validate:"required"`
+	Review  string `json:"review" validate:"required"`
+}
+
+func (r *ReviewRequest) Model() models.Reviews {
+	return models.Reviews{
+		MovieID: r.MovieID,
+		Review:  &r.Review,
+	}
+}
+
+// ReviewExportColumns orders the CSV columns of a review export.
+var ReviewExportColumns = []string{"review_id", "movie_id", "reviewer_id", "review", "version", "created_at", "updated_at"}
+
+type ReviewResponse struct {
+	ReviewID   string `json:"review_id"`
+	MovieID    int    `json:"movie_id"`
+	ReviewerID string `json:"reviewer_id"`
+	Review     string `json:"review"`
+	Version    int64  `json:"version"`
+	CreatedAt  string `json:"created_at"`
+	UpdatedAt  string `json:"updated_at"`
+	DeletedAt  string `json:"deleted_at,omitempty"`
+	DeletedBy  string `json:"deleted_by,omitempty"`
+}
+
+func NewReviewResponse(review *models.Reviews) ReviewResponse {
+	return ReviewResponse{
+		ReviewID:   review.ID.Hex(),
+		MovieID:    review.MovieID,
+		ReviewerID: review.ReviewerID.Hex(),
+		Review:     deref(review.Review),
+		Version:    review.Version,
+		CreatedAt:  timestamp(review.CreatedAt),
+		UpdatedAt:  timestamp(review.UpdatedAt),
+		DeletedAt:  optionalTimestamp(review.DeletedAt),
+		DeletedBy:  review.DeletedBy,
+	}
+}
+
+func NewReviewResponses(reviews []models.Reviews) []ReviewResponse {
+	responses := make([]ReviewResponse, 0, len(reviews))
+	for i := range reviews {
+		responses = append(responses, NewReviewResponse(&reviews[i]))
+	}
+	return responses
+}
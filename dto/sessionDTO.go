@@ -0,0 +1,32 @@
+package dto
+
+import "github.com/mayurvarma14/go-movie-review/models"
+
+type SessionResponse struct {
+	SessionID   string `json:"session_id"`
+	DeviceLabel string `json:"device_label"`
+	UserAgent   string `json:"user_agent"`
+	IP          string `json:"ip"`
+	CreatedAt   string `json:"created_at"`
+	LastSeenAt  string `json:"last_seen_at"`
+	ExpiresAt   string `json:"expires_at"`
+	Current     bool   `json:"current"`
+}
+
+// NewSessionResponses marks the session behind the caller's token as current.
+func NewSessionResponses(sessions []models.Session, currentSessionID string) []SessionResponse {
+	responses := make([]SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		responses = append(responses, SessionResponse{
+			SessionID:   session.ID.Hex(),
+			DeviceLabel: session.DeviceLabel,
+			UserAgent:   session.UserAgent,
+			IP:          session.IP,
+			CreatedAt:   timestamp(session.CreatedAt),
+			LastSeenAt:  timestamp(session.LastSeenAt),
+			ExpiresAt:   timestamp(session.ExpiresAt),
+			Current:     session.ID.Hex() == currentSessionID,
+		})
+	}
+	return responses
+}
@@ -0,0 +1,67 @@
+package dto
+
+import "github.com/mayurvarma14/go-movie-review/models"
+
+type SignUpRequest struct {
+	Name     string `json:"name" validate:"required,min=4,max=100"`
+	Username string `json:"username" validate:"required,min=4,max=100"`
+	Password string `json:"password" validate:"required"`
+	Email    string `json:"email" validate:"email,required"`
+	UserType string `json:"user_type" validate:"required,oneof=ADMIN USER"`
+}
+
+// Model builds the document to store. Identifiers, timestamps and the password
+// hash are filled in by the caller.
+func (r *SignUpRequest) Model() models.User {
+	return models.User{
+		Name:     &r.Name,
+		Username: &r.Username,
+		Email:    &r.Email,
+		UserType: &r.UserType,
+	}
+}
+
+type LoginRequest struct {
+	Email       string `json:"email" validate:"required,email"`
+	Password    string `json:"password" validate:"required"`
+	DeviceLabel string `json:"device_label" validate:"max=100"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// UserResponse is the only shape in which users leave the API. It has no
+// password, token or MFA secret fields, so they cannot be serialized by
+// accident.
+type UserResponse struct {
+	UserID     string `json:"user_id"`
+	Name       string `json:"name"`
+	Username   string `json:"username"`
+	Email      string `json:"email"`
+	UserType   string `json:"user_type"`
+	MFAEnabled bool   `json:"mfa_enabled"`
+	CreatedAt  string `json:"created_at"`
+	UpdatedAt  string `json:"updated_at"`
+}
+
+func NewUserResponse(user *models.User) UserResponse {
+	return UserResponse{
+		UserID:     user.UserID,
+		Name:       deref(user.Name),
+		Username:   deref(user.Username),
+		Email:      deref(user.Email),
+		UserType:   deref(user.UserType),
+		MFAEnabled: user.MFAEnabled,
+		CreatedAt:  timestamp(user.CreatedAt),
+		UpdatedAt:  timestamp(user.UpdatedAt),
+	}
+}
+
+func NewUserResponses(users []models.User) []UserResponse {
+	responses := make([]UserResponse, 0, len(users))
+	for i := range users {
+		responses = append(responses, NewUserResponse(&users[i]))
+	}
+	return responses
+}
@@ -0,0 +1,211 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mayurvarma14/go-movie-review/internals/metrics"
+	"github.com/mayurvarma14/go-movie-review/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+const (
+	AuditCreate  = "create"
+	AuditUpdate  = "update"
+	AuditDelete  = "delete"
+	AuditRestore = "restore"
+	AuditPurge   = "purge"
+)
+
+// AuditChange is one write for RecordAudits.
+type AuditChange struct {
+	Action     string
+	ResourceID string
+	Before     any
+	After      any
+}
+
+// RecordAudit appends an entry for an administrative write made by the
+// authenticated user. before is nil for creations. The write has already
+// happened by the time it is audited, so a failure to record it is only
+// logged and counted; the request still succeeds.
+func RecordAudit(ctx context.Context, c *gin.Context, auditCollection *mongo.Collection, action, resource, resourceID string, before, after any) {
+	RecordAudits(ctx, c, auditCollection, resource, []AuditChange{{Action: action, ResourceID: resourceID, Before: before, After: after}})
+}
+
+// RecordAudits appends the entries for several writes to one kind of
+// resource in a single insert, as bulk operations do.
+func RecordAudits(ctx context.Context, c *gin.Context, auditCollection *mongo.Collection, resource string, changes []AuditChange) {
+	RecordAuditsAs(ctx, RequestActor(c), auditCollection, resource, changes)
+}
+
+// AuditActor is who an audit entry attributes a write to and where it came
+// from.
+type AuditActor struct {
+	ID        string
+	IP        string
+	RequestID string
+}
+
+// RequestActor is the authenticated user making the request.
+func RequestActor(c *gin.Context) AuditActor {
+	return AuditActor{ID: c.GetString("uid"), IP: c.ClientIP(), RequestID: c.GetString("request_id")}
+}
+
+// RecordAuditsAs is RecordAudits for writes made outside a request, such as
+// by the admin CLI. Like RecordAudit it only logs a failure, and counts it in
+// audit_write_failures_total; use WriteAudits when the write should not go
+// ahead unaudited.
+func RecordAuditsAs(ctx context.Context, actor AuditActor, auditCollection *mongo.Collection, resource string, changes []AuditChange) {
+	if err := WriteAudits(ctx, actor, auditCollection, resource, changes); err != nil {
+		metrics.AuditWriteFailures.Add(float64(len(changes)))
+		slog.ErrorContext(ctx, "failed to record audit entries", "error", err, "resource", resource, "count", len(changes))
+	}
+}
+
+// WriteAudits inserts the entries for changes and reports any failure to the
+// caller, which is expected to audit before it writes.
+func WriteAudits(ctx context.Context, actor AuditActor, auditCollection *mongo.Collection, resource string, changes []AuditChange) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	entries := make([]models.AuditEntry, 0, len(changes))
+	for _, change := range changes {
+		entry := models.AuditEntry{
+			ID:         bson.NewObjectID(),
+			ActorID:    actor.ID,
+			Action:     change.Action,
+			Resource:   resource,
+			ResourceID: change.ResourceID,
+			IP:         actor.IP,
+			RequestID:  actor.RequestID,
+			CreatedAt:  now,
+		}
+
+		var err error
+		if entry.Before, err = snapshot(change.Before); err == nil {
+			entry.After, err = snapshot(change.After)
+		}
+		if err != nil {
+			return fmt.Errorf("%s %s %s: %w", change.Action, resource, change.ResourceID, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if _, err := auditCollection.InsertMany(ctx, entries); err != nil {
+		return fmt.Errorf("inserting audit entries: %w", err)
+	}
+	return nil
+}
+
+// SystemActor is the actor for writes the server makes on its own, such as
+// purging the trash.
+var SystemActor = AuditActor{ID: "system"}
+
+// trashIDFields is the field each trash collection's audit entries use as
+// the resource ID, matching the entries its handlers write.
+var trashIDFields = map[string]string{"movie": "movie_id", "genre": "genre_id", "review": "_id"}
+
+// PurgeAuditor returns a database.PurgeAuditor that records a purge entry,
+// with the document as it was, for every document in a batch on behalf of
+// actor.
+func PurgeAuditor(actor AuditActor, auditCollection *mongo.Collection) func(ctx context.Context, collection string, docs []bson.Raw) error {
+	return func(ctx context.Context, collection string, docs []bson.Raw) error {
+		field, ok := trashIDFields[collection]
+		if !ok {
+			field = "_id"
+		}
+		changes := make([]AuditChange, 0, len(docs))
+		for _, doc := range docs {
+			changes = append(changes, AuditChange{Action: AuditPurge, ResourceID: rawID(doc.Lookup(field)), Before: doc})
+		}
+		return WriteAudits(ctx, actor, auditCollection, collection, changes)
+	}
+}
+
+func rawID(value bson.RawValue) string {
+	switch value.Type {
+	case bson.TypeObjectID:
+		return value.ObjectID().Hex()
+	case bson.TypeInt32:
+		return strconv.FormatInt(int64(value.Int32()), 10)
+	case bson.TypeInt64:
+		return strconv.FormatInt(value.Int64(), 10)
+	case bson.TypeString:
+		return value.StringValue()
+	}
+	return value.String()
+}
+
+func snapshot(doc any) (bson.Raw, error) {
+	if doc == nil {
+		return nil, nil
+	}
+	if raw, ok := doc.(bson.Raw); ok {
+		return raw, nil
+	}
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("encoding audit snapshot: %w", err)
+	}
+	return raw, nil
+}
+
+// Updated returns a copy of doc as update leaves it, so a handler that read
+// the document atomically with its write (ReturnDocument Before) has both
+// sides of it. It understands the operators VersionedUpdate, SoftDelete and
+// Restore produce: $set, $unset and $inc on top-level fields.
+func Updated[T any](doc *T, update bson.M) (*T, error) {
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("encoding document: %w", err)
+	}
+	var fields bson.M
+	if err := bson.Unmarshal(raw, &fields); err != nil {
+		return nil, fmt.Errorf("decoding document: %w", err)
+	}
+
+	if set, ok := update["$set"].(bson.M); ok {
+		for key, value := range set {
+			fields[key] = value
+		}
+	}
+	if unset, ok := update["$unset"].(bson.M); ok {
+		for key := range unset {
+			delete(fields, key)
+		}
+	}
+	if inc, ok := update["$inc"].(bson.M); ok {
+		for key, value := range inc {
+			fields[key] = toInt64(fields[key]) + toInt64(value)
+		}
+	}
+
+	if raw, err = bson.Marshal(fields); err != nil {
+		return nil, fmt.Errorf("encoding document: %w", err)
+	}
+	var updated T
+	if err := bson.Unmarshal(raw, &updated); err != nil {
+		return nil, fmt.Errorf("decoding document: %w", err)
+	}
+	return &updated, nil
+}
+
+func toInt64(value any) int64 {
+	switch v := value.(type) {
+	case int:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case int64:
+		return v
+	}
+	return 0
+}
@@ -1,18 +1,44 @@
 package helpers
 
 import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
 	"errors"
 	"fmt"
-	"log"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mayurvarma14/go-movie-review/internals/apperrors"
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/bcrypt"
 )
 
+type argon2Params struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+	saltLength  int
+	keyLength   uint32
+}
+
+// Current Argon2id parameters; hashes produced with anything else (including
+// legacy bcrypt hashes) are upgraded on the next successful login.
+var passwordHashParams = argon2Params{
+	memory:      19 * 1024,
+	iterations:  2,
+	parallelism: 1,
+	saltLength:  16,
+	keyLength:   32,
+}
+
 func VerifyUserType(c *gin.Context, role string) error {
 	userType := c.GetString("user_type")
 	if userType != role {
-		return fmt.Errorf("unauthorized: user is not a %s", role)
+		return apperrors.Forbidden(fmt.Sprintf("user is not a %s", role))
+	}
+	if role == AdminRole && c.GetBool("mfa_pending") {
+		return apperrors.Forbidden("MFA enrollment required for ADMIN accounts")
 	}
 	return nil
 }
@@ -20,24 +46,58 @@ func VerifyUserType(c *gin.Context, role string) error {
 func MatchUserID(c *gin.Context, userID string) error {
 	uid := c.GetString("uid")
 	if uid != userID {
-		return errors.New("unauthorized: user ID mismatch")
+		return apperrors.Forbidden("user ID mismatch")
 	}
 	return nil
 }
 
 func MaskPassword(password string) (string, error) {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
-	if err != nil {
-		log.Printf("Error hashing password: %v", err)
+	p := passwordHashParams
+	salt := make([]byte, p.saltLength)
+	if _, err := rand.Read(salt); err != nil {
 		return "", fmt.Errorf("hashing password: %w", err)
 	}
-	return string(bytes), nil
+
+	key := argon2.IDKey([]byte(password), salt, p.iterations, p.memory, p.parallelism, p.keyLength)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, p.memory, p.iterations, p.parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
 }
 
 func ConfirmPassword(hashedPassword, userPassword string) (bool, error) {
+	if !strings.HasPrefix(hashedPassword, "$argon2id$") {
+		return confirmBcryptPassword(hashedPassword, userPassword)
+	}
+
+	p, salt, key, err := decodeArgon2Hash(hashedPassword)
+	if err != nil {
+		return false, fmt.Errorf("comparing password: %w", err)
+	}
+
+	candidate := argon2.IDKey([]byte(userPassword), salt, p.iterations, p.memory, p.parallelism, p.keyLength)
+	if subtle.ConstantTimeCompare(key, candidate) != 1 {
+		return false, errors.New("incorrect password")
+	}
+	return true, nil
+}
+
+// PasswordNeedsRehash reports whether a stored hash was produced by an older
+// algorithm or with weaker parameters than passwordHashParams.
+func PasswordNeedsRehash(hashedPassword string) bool {
+	p, _, _, err := decodeArgon2Hash(hashedPassword)
+	if err != nil {
+		return true
+	}
+	current := passwordHashParams
+	return p.memory != current.memory || p.iterations != current.iterations ||
+		p.parallelism != current.parallelism || p.keyLength != current.keyLength
+}
+
+func confirmBcryptPassword(hashedPassword, userPassword string) (bool, error) {
 	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(userPassword))
 	if err != nil {
-		log.Printf("Error comparing password: %v", err)
 		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
 			return false, errors.New("incorrect password")
 		}
@@ -45,3 +105,32 @@ func ConfirmPassword(hashedPassword, userPassword string) (bool, error) {
 	}
 	return true, nil
 }
+
+func decodeArgon2Hash(encoded string) (argon2Params, []byte, []byte, error) {
+	var p argon2Params
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return p, nil, nil, errors.New("unsupported password hash format")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return p, nil, nil, errors.New("unsupported argon2 version")
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.memory, &p.iterations, &p.parallelism); err != nil {
+		return p, nil, nil, fmt.Errorf("parsing argon2 parameters: %w", err)
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return p, nil, nil, fmt.Errorf("decoding salt: %w", err)
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return p, nil, nil, fmt.Errorf("decoding hash: %w", err)
+	}
+	p.saltLength = len(salt)
+	p.keyLength = uint32(len(key))
+
+	return p, salt, key, nil
+}
@@ -4,3 +4,9 @@ const (
 	AdminRole = "ADMIN"
 	UserRole  = "USER"
 )
+
+const (
+	MFAIssuer           = "GoMovieReview"
+	MFAChallengePurpose = "mfa_challenge"
+	RefreshPurpose      = "refresh"
+)
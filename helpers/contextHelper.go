@@ -0,0 +1,21 @@
+package helpers
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const DefaultRequestTimeout = 10 * time.Second
+
+// RequestContext derives a context from the incoming request so database
+// calls stop as soon as the client goes away, bounded by the deadline set for
+// the route with middleware.Timeout.
+func RequestContext(c *gin.Context) (context.Context, context.CancelFunc) {
+	timeout := c.GetDuration("request_timeout")
+	if timeout <= 0 {
+		timeout = DefaultRequestTimeout
+	}
+	return context.WithTimeout(c.Request.Context(), timeout)
+}
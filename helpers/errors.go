@@ -1,9 +1,59 @@
 package helpers
 
 import (
+	"fmt"
+	"reflect"
+	"strings"
+
 	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
 )
 
-func HandleError(c *gin.Context, status int, err error) {
-	c.JSON(status, gin.H{"error": err.Error()})
+// HandleError records err on the context and stops the handler chain. The
+// response itself is written by middleware.ErrorHandler.
+func HandleError(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Abort()
+}
+
+// NewValidator returns a validator that reports fields by their JSON names so
+// per-field errors match what clients sent.
+func NewValidator() *validator.Validate {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+	return validate
+}
+
+// ValidationMessage describes a failed validator rule in words clients can
+// show next to the field.
+func ValidationMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "required_without":
+		return fmt.Sprintf("is required when %s is not provided", fe.Param())
+	case "email":
+		return "must be a valid email address"
+	case "min":
+		return fmt.Sprintf("must be at least %s characters", fe.Param())
+	case "max":
+		return fmt.Sprintf("must be at most %s characters", fe.Param())
+	case "len":
+		return fmt.Sprintf("must be exactly %s characters", fe.Param())
+	case "numeric":
+		return "must be numeric"
+	case "oneof":
+		return fmt.Sprintf("must be one of: %s", strings.ReplaceAll(fe.Param(), " ", ", "))
+	default:
+		return fmt.Sprintf("failed on the '%s' rule", fe.Tag())
+	}
 }
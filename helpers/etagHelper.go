@@ -0,0 +1,103 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mayurvarma14/go-movie-review/internals/apperrors"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// ETag renders a document version as a strong entity tag. Movies, genres and
+// reviews carry a version that starts at 1 and grows by one on every write.
+func ETag(version int64) string {
+	return strconv.Quote(strconv.FormatInt(version, 10))
+}
+
+// VersionedUpdate wraps the fields of a write so that it also bumps the
+// document's version.
+func VersionedUpdate(set bson.M) bson.M {
+	return bson.M{"$set": set, "$inc": bson.M{"version": 1}}
+}
+
+// ConditionalGet sets the ETag for version and answers 304 Not Modified when
+// If-None-Match already names it. Handlers stop when it returns true.
+func ConditionalGet(c *gin.Context, version int64) bool {
+	etag := ETag(version)
+	c.Header("ETag", etag)
+
+	for _, candidate := range strings.Split(c.GetHeader("If-None-Match"), ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			c.Status(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
+
+// IfMatch returns the versions the If-Match header allows a write to replace.
+// It returns nil, meaning any version, when the header is absent or "*". Weak
+// or foreign tags never match, as If-Match requires strong comparison.
+func IfMatch(c *gin.Context) []int64 {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return nil
+	}
+
+	versions := []int64{}
+	for _, candidate := range strings.Split(header, ",") {
+		unquoted, err := strconv.Unquote(strings.TrimSpace(candidate))
+		if err != nil {
+			continue
+		}
+		if version, err := strconv.ParseInt(unquoted, 10, 64); err == nil {
+			versions = append(versions, version)
+		}
+	}
+	return versions
+}
+
+// MatchVersions narrows filter to the versions If-Match allows.
+func MatchVersions(filter bson.M, versions []int64) bson.M {
+	if versions != nil {
+		filter["version"] = bson.M{"$in": versions}
+	}
+	return filter
+}
+
+// VersionMatches reports whether a document at version may be written.
+func VersionMatches(version int64, versions []int64) bool {
+	if versions == nil {
+		return true
+	}
+	for _, v := range versions {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
+
+// MissedWrite explains why a conditional write matched no document: either
+// the document does not exist or its version no longer matches If-Match.
+func MissedWrite(ctx context.Context, collection *mongo.Collection, filter bson.M, versions []int64, notFound string) error {
+	if versions == nil {
+		return apperrors.NotFound(notFound)
+	}
+
+	delete(filter, "version")
+	count, err := collection.CountDocuments(ctx, filter)
+	if err != nil {
+		return apperrors.Internal(fmt.Errorf("checking precondition: %w", err))
+	}
+	if count == 0 {
+		return apperrors.NotFound(notFound)
+	}
+	return apperrors.PreconditionFailed("resource was modified since it was fetched")
+}
@@ -0,0 +1,173 @@
+package helpers
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mayurvarma14/go-movie-review/internals/apperrors"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+const (
+	ExportCSV    = "csv"
+	ExportJSON   = "json"
+	ExportNDJSON = "ndjson"
+)
+
+var exportContentTypes = map[string]string{
+	ExportCSV:    CSVContentType + "; charset=utf-8",
+	ExportJSON:   "application/json; charset=utf-8",
+	ExportNDJSON: NDJSONContentType,
+}
+
+// ExportFormat reads the format query parameter, which defaults to json.
+func ExportFormat(c *gin.Context) (string, error) {
+	return ParseExportFormat(c.DefaultQuery("format", ExportJSON))
+}
+
+// ParseExportFormat checks that format is one exports can be written in.
+func ParseExportFormat(format string) (string, error) {
+	if _, ok := exportContentTypes[format]; !ok {
+		return "", apperrors.Validation("format must be one of: csv, json, ndjson")
+	}
+	return format, nil
+}
+
+// StreamExport writes every document cursor yields as a download named after
+// name and today's date. Documents are decoded into M and mapped to their
+// response shape with toRow one at a time, so an export never holds the
+// result set in memory. CSV has a header row of columns, which name the
+// response's JSON fields.
+//
+// The status is sent with the first bytes, so a failure after that can only
+// be logged and cuts the download short.
+func StreamExport[M any, R any](ctx context.Context, c *gin.Context, cursor *mongo.Cursor, format, name string, columns []string, toRow func(*M) R) {
+	filename := fmt.Sprintf("%s-%s.%s", name, time.Now().UTC().Format("20060102"), format)
+	c.Header("Content-Type", exportContentTypes[format])
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	c.Status(http.StatusOK)
+
+	count, err := WriteExport(ctx, c.Writer, cursor, format, columns, toRow)
+	if err != nil {
+		slog.ErrorContext(ctx, "export stopped early", "error", err, "export", name, "written", count)
+	}
+}
+
+// WriteExport writes every document cursor yields to out in format, as
+// StreamExport does, and returns how many it wrote.
+func WriteExport[M any, R any](ctx context.Context, out io.Writer, cursor *mongo.Cursor, format string, columns []string, toRow func(*M) R) (int, error) {
+	writer := newExportWriter(out, format, columns)
+	err := writer.begin()
+	count := 0
+	for err == nil && cursor.Next(ctx) {
+		var doc M
+		if err = cursor.Decode(&doc); err == nil {
+			err = writer.write(toRow(&doc))
+			count++
+		}
+	}
+	if err == nil {
+		err = cursor.Err()
+	}
+	if err == nil {
+		err = writer.end()
+	}
+	return count, err
+}
+
+type exportWriter struct {
+	out     io.Writer
+	format  string
+	columns []string
+	csv     *csv.Writer
+	rows    int
+}
+
+func newExportWriter(out io.Writer, format string, columns []string) *exportWriter {
+	w := &exportWriter{out: out, format: format, columns: columns}
+	if format == ExportCSV {
+		w.csv = csv.NewWriter(out)
+	}
+	return w
+}
+
+func (w *exportWriter) begin() error {
+	switch w.format {
+	case ExportCSV:
+		return w.csv.Write(w.columns)
+	case ExportJSON:
+		_, err := io.WriteString(w.out, "[")
+		return err
+	}
+	return nil
+}
+
+func (w *exportWriter) write(row any) error {
+	encoded, err := json.Marshal(row)
+	if err != nil {
+		return err
+	}
+	w.rows++
+
+	switch w.format {
+	case ExportCSV:
+		record, err := csvRecord(encoded, w.columns)
+		if err != nil {
+			return err
+		}
+		return w.csv.Write(record)
+	case ExportJSON:
+		if w.rows > 1 {
+			encoded = append([]byte(","), encoded...)
+		}
+	case ExportNDJSON:
+		encoded = append(encoded, '\n')
+	}
+	_, err = w.out.Write(encoded)
+	return err
+}
+
+func (w *exportWriter) end() error {
+	switch w.format {
+	case ExportCSV:
+		w.csv.Flush()
+		return w.csv.Error()
+	case ExportJSON:
+		_, err := io.WriteString(w.out, "]\n")
+		return err
+	}
+	return nil
+}
+
+// csvRecord picks columns out of a JSON object. Missing fields are empty.
+func csvRecord(encoded []byte, columns []string) ([]string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+	var fields map[string]any
+	if err := decoder.Decode(&fields); err != nil {
+		return nil, err
+	}
+
+	record := make([]string, len(columns))
+	for i, column := range columns {
+		switch value := fields[column].(type) {
+		case nil:
+		case string:
+			record[i] = value
+		case json.Number:
+			record[i] = value.String()
+		default:
+			record[i] = fmt.Sprint(value)
+		}
+	}
+	return record, nil
+}
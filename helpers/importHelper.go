@@ -0,0 +1,188 @@
+package helpers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/mayurvarma14/go-movie-review/internals/apperrors"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const (
+	CSVContentType    = "text/csv"
+	NDJSONContentType = "application/x-ndjson"
+)
+
+// ImportRow is one record of an import upload: its decoded value, or why it
+// could not be decoded. Line is where the record starts in the upload.
+type ImportRow[T any] struct {
+	Line  int
+	Value T
+	Err   error
+}
+
+// ReadImport decodes an import upload, picking the format from its content
+// type. See DecodeImport.
+func ReadImport[T any](c *gin.Context, columns []string, fromCSV func(record map[string]string) (T, error)) ([]ImportRow[T], error) {
+	mediaType, _, _ := mime.ParseMediaType(c.ContentType())
+	return DecodeImport(c.Request.Body, mediaType, columns, fromCSV)
+}
+
+// DecodeImport decodes an import in the given media type. CSV needs a header row naming some of
+// the columns, and fromCSV turns each record, keyed by column, into a T.
+// NDJSON holds one JSON object per line and decodes like a request body. A
+// record that cannot be decoded fails on its own; only an unreadable upload
+// fails the request.
+func DecodeImport[T any](body io.Reader, mediaType string, columns []string, fromCSV func(record map[string]string) (T, error)) ([]ImportRow[T], error) {
+	switch mediaType {
+	case CSVContentType:
+		return readCSV(body, columns, fromCSV)
+	case NDJSONContentType, "application/ndjson":
+		return readNDJSON[T](body)
+	default:
+		return nil, apperrors.UnsupportedMediaType("imports must use " + CSVContentType + " or " + NDJSONContentType)
+	}
+}
+
+func readCSV[T any](body io.Reader, columns []string, fromCSV func(record map[string]string) (T, error)) ([]ImportRow[T], error) {
+	reader := csv.NewReader(body)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, apperrors.Validation("CSV upload must start with a header row")
+	}
+	if err != nil {
+		return nil, uploadError(err)
+	}
+	for i, name := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(name))
+		if !slices.Contains(columns, header[i]) {
+			return nil, apperrors.Validation(fmt.Sprintf("unknown CSV column %q, expected some of: %s", name, strings.Join(columns, ", ")))
+		}
+	}
+
+	var rows []ImportRow[T]
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil && !errors.Is(err, csv.ErrFieldCount) {
+			return nil, uploadError(err)
+		}
+		line, _ := reader.FieldPos(0)
+		if err != nil {
+			rows = append(rows, ImportRow[T]{Line: line, Err: fmt.Errorf("has %d fields, the header has %d", len(record), len(header))})
+			continue
+		}
+
+		fields := make(map[string]string, len(header))
+		for i, name := range header {
+			fields[name] = strings.TrimSpace(record[i])
+		}
+		value, err := fromCSV(fields)
+		rows = append(rows, ImportRow[T]{Line: line, Value: value, Err: err})
+	}
+}
+
+// readNDJSON reads whole lines however long they are; the upload as a whole
+// is bounded by the route's body limit.
+func readNDJSON[T any](body io.Reader) ([]ImportRow[T], error) {
+	reader := bufio.NewReader(body)
+
+	var rows []ImportRow[T]
+	for line := 1; ; line++ {
+		raw, err := reader.ReadBytes('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, uploadError(err)
+		}
+
+		if raw = bytes.TrimSpace(raw); len(raw) > 0 {
+			var value T
+			decoder := json.NewDecoder(bytes.NewReader(raw))
+			if binding.EnableDecoderDisallowUnknownFields {
+				decoder.DisallowUnknownFields()
+			}
+			decodeErr := decoder.Decode(&value)
+			rows = append(rows, ImportRow[T]{Line: line, Value: value, Err: decodeErr})
+		}
+
+		if err != nil {
+			return rows, nil
+		}
+	}
+}
+
+// uploadError keeps oversized bodies recognizable to the error handler,
+// which answers them with 413.
+func uploadError(err error) error {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return err
+	}
+	return apperrors.Validation("upload could not be read: " + err.Error())
+}
+
+// ImportError describes why a row failed, in the words the error handler
+// uses for request bodies.
+func ImportError(err error) string {
+	var validationErrs validator.ValidationErrors
+	var typeErr *json.UnmarshalTypeError
+	var syntaxErr *json.SyntaxError
+	switch {
+	case errors.As(err, &validationErrs):
+		messages := make([]string, 0, len(validationErrs))
+		for _, fe := range validationErrs {
+			messages = append(messages, fe.Field()+" "+ValidationMessage(fe))
+		}
+		return strings.Join(messages, "; ")
+	case errors.As(err, &typeErr):
+		return fmt.Sprintf("%s must be of type %s", typeErr.Field, typeErr.Type)
+	case errors.As(err, &syntaxErr):
+		return "is not valid JSON"
+	default:
+		return strings.TrimPrefix(err.Error(), "json: ")
+	}
+}
+
+// BulkWrite runs an import's writes as one unordered bulk write, so a write
+// that fails does not stop the rest. It returns why each failed write failed,
+// by index.
+func BulkWrite(ctx context.Context, collection *mongo.Collection, writes []mongo.WriteModel) (map[int]error, error) {
+	failed := map[int]error{}
+	_, err := collection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+
+	var bulkErr mongo.BulkWriteException
+	if errors.As(err, &bulkErr) && bulkErr.WriteConcernError == nil {
+		for _, writeErr := range bulkErr.WriteErrors {
+			failed[writeErr.Index] = writeErr.WriteError
+		}
+		return failed, nil
+	}
+	return failed, err
+}
+
+// ImportWriteError describes why an import row's write failed. A duplicate
+// key means another live document, written since the import read the
+// collection, already has the row's key.
+func ImportWriteError(err error, key string) string {
+	if mongo.IsDuplicateKeyError(err) {
+		return key + " already exists"
+	}
+	return "could not be written"
+}
@@ -0,0 +1,44 @@
+package helpers
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadNDJSON(t *testing.T) {
+	type record struct {
+		Name string `json:"name"`
+	}
+	long := strings.Repeat("x", 2<<20)
+
+	tests := []struct {
+		name      string
+		body      string
+		wantLines []int
+		wantNames []string
+		wantErrs  []bool
+	}{
+		{"no trailing newline", `{"name":"a"}` + "\n" + `{"name":"b"}`, []int{1, 2}, []string{"a", "b"}, []bool{false, false}},
+		{"blank lines keep numbering", "\n" + `{"name":"a"}` + "\n\n  \n" + `{"name":"b"}` + "\n", []int{2, 5}, []string{"a", "b"}, []bool{false, false}},
+		{"bad row fails alone", `{"name":` + "\n" + `{"name":"b"}` + "\n", []int{1, 2}, []string{"", "b"}, []bool{true, false}},
+		{"line longer than a scanner buffer", `{"name":"` + long + `"}` + "\n", []int{1}, []string{long}, []bool{false}},
+		{"empty", "", nil, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := readNDJSON[record](strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(rows) != len(tt.wantLines) {
+				t.Fatalf("got %d rows, want %d", len(rows), len(tt.wantLines))
+			}
+			for i, row := range rows {
+				if row.Line != tt.wantLines[i] || row.Value.Name != tt.wantNames[i] || (row.Err != nil) != tt.wantErrs[i] {
+					t.Errorf("row %d: got line %d, name of %d bytes, error %v", i, row.Line, len(row.Value.Name), row.Err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package helpers
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/mayurvarma14/go-movie-review/internals/config"
+)
+
+//go:embed commonPasswords.txt
+var commonPasswordList string
+
+var commonPasswords = func() map[string]struct{} {
+	set := make(map[string]struct{})
+	for _, line := range strings.Split(commonPasswordList, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			set[strings.ToLower(line)] = struct{}{}
+		}
+	}
+	return set
+}()
+
+type PasswordPolicy struct {
+	MinLength          int
+	MaxLength          int
+	RequireUpper       bool
+	RequireLower       bool
+	RequireDigit       bool
+	RequireSymbol      bool
+	RejectCommon       bool
+	RejectPersonalInfo bool
+}
+
+type PasswordViolation struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+type PasswordPolicyError struct {
+	Violations []PasswordViolation
+}
+
+func (e *PasswordPolicyError) Error() string {
+	messages := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		messages = append(messages, v.Message)
+	}
+	return "password does not meet policy: " + strings.Join(messages, "; ")
+}
+
+func NewPasswordPolicy(cfg config.PasswordConfig) PasswordPolicy {
+	return PasswordPolicy(cfg)
+}
+
+// Validate checks password against every rule and reports all failures at
+// once rather than stopping at the first one.
+func (p PasswordPolicy) Validate(password, username, email string) error {
+	var violations []PasswordViolation
+	fail := func(rule, format string, args ...any) {
+		violations = append(violations, PasswordViolation{Rule: rule, Message: fmt.Sprintf(format, args...)})
+	}
+
+	length := len([]rune(password))
+	if length < p.MinLength {
+		fail("min_length", "must be at least %d characters", p.MinLength)
+	}
+	if p.MaxLength > 0 && length > p.MaxLength {
+		fail("max_length", "must be at most %d characters", p.MaxLength)
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+	if p.RequireUpper && !hasUpper {
+		fail("uppercase", "must contain an uppercase letter")
+	}
+	if p.RequireLower && !hasLower {
+		fail("lowercase", "must contain a lowercase letter")
+	}
+	if p.RequireDigit && !hasDigit {
+		fail("digit", "must contain a digit")
+	}
+	if p.RequireSymbol && !hasSymbol {
+		fail("symbol", "must contain a symbol")
+	}
+
+	lowered := strings.ToLower(password)
+	if p.RejectCommon {
+		if _, ok := commonPasswords[lowered]; ok {
+			fail("common", "is too common")
+		}
+	}
+
+	if p.RejectPersonalInfo {
+		localPart, _, _ := strings.Cut(strings.ToLower(email), "@")
+		for _, info := range []string{strings.ToLower(username), localPart} {
+			if len(info) >= 3 && strings.Contains(lowered, info) {
+				fail("personal_info", "must not contain your username or email")
+				break
+			}
+		}
+	}
+
+	if len(violations) > 0 {
+		return &PasswordPolicyError{Violations: violations}
+	}
+	return nil
+}
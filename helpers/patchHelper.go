@@ -0,0 +1,109 @@
+package helpers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/mayurvarma14/go-movie-review/internals/apperrors"
+)
+
+const MergePatchContentType = "application/merge-patch+json"
+
+var errPatchNotObject = errors.New("merge patch must be a JSON object")
+
+// BindMergePatch applies the request body, an RFC 7396 JSON merge patch, to
+// current and decodes the result into a fresh T. current is the resource in
+// its request shape, so the merged value can be validated like a PUT body.
+func BindMergePatch[T any](c *gin.Context, current T) (T, error) {
+	var merged T
+
+	if mediaType, _, _ := mime.ParseMediaType(c.ContentType()); mediaType != MergePatchContentType {
+		return merged, apperrors.UnsupportedMediaType("PATCH requests must use " + MergePatchContentType)
+	}
+
+	patch, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return merged, apperrors.InvalidInput(err)
+	}
+
+	doc, err := json.Marshal(current)
+	if err != nil {
+		return merged, apperrors.Internal(err)
+	}
+
+	result, err := ApplyMergePatch(doc, patch)
+	if errors.Is(err, errPatchNotObject) {
+		return merged, apperrors.Validation(err.Error())
+	}
+	if err != nil {
+		return merged, apperrors.InvalidInput(err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(result))
+	if binding.EnableDecoderDisallowUnknownFields {
+		decoder.DisallowUnknownFields()
+	}
+	if err := decoder.Decode(&merged); err != nil {
+		return merged, apperrors.InvalidInput(err)
+	}
+	return merged, nil
+}
+
+// ApplyMergePatch merges patch into doc as RFC 7396 describes: objects merge
+// member by member, null removes a member and anything else replaces it.
+func ApplyMergePatch(doc, patch []byte) ([]byte, error) {
+	patchValue, err := decodeJSON(patch)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := patchValue.(map[string]any); !ok {
+		return nil, errPatchNotObject
+	}
+
+	docValue, err := decodeJSON(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(mergePatch(docValue, patchValue))
+}
+
+func mergePatch(target, patch any) any {
+	patchObject, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]any)
+	if !ok {
+		targetObject = make(map[string]any, len(patchObject))
+	}
+	for name, value := range patchObject {
+		if value == nil {
+			delete(targetObject, name)
+			continue
+		}
+		targetObject[name] = mergePatch(targetObject[name], value)
+	}
+	return targetObject
+}
+
+// decodeJSON keeps numbers as json.Number so large IDs survive the round trip.
+func decodeJSON(data []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	if decoder.More() {
+		return nil, errors.New("unexpected data after JSON value")
+	}
+	return value, nil
+}
@@ -0,0 +1,70 @@
+package helpers
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// The examples from RFC 7396 Appendix A.
+func TestMergePatchRFC7396(t *testing.T) {
+	tests := []struct {
+		target, patch, want string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target+" + "+tt.patch, func(t *testing.T) {
+			got, err := json.Marshal(mergePatch(mustDecode(t, tt.target), mustDecode(t, tt.patch)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, _ := json.Marshal(mustDecode(t, tt.want))
+			if string(got) != string(want) {
+				t.Errorf("got %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestApplyMergePatchRejectsNonObjectPatch(t *testing.T) {
+	for _, patch := range []string{`["c"]`, `null`, `"bar"`, `1`} {
+		if _, err := ApplyMergePatch([]byte(`{"a":"b"}`), []byte(patch)); !errors.Is(err, errPatchNotObject) {
+			t.Errorf("patch %s: got error %v, want %v", patch, err, errPatchNotObject)
+		}
+	}
+}
+
+func TestApplyMergePatchKeepsLargeNumbers(t *testing.T) {
+	got, err := ApplyMergePatch([]byte(`{"id":9007199254740993,"n":1}`), []byte(`{"n":2}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"id":9007199254740993,"n":2}`; string(got) != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func mustDecode(t *testing.T, data string) any {
+	t.Helper()
+	value, err := decodeJSON([]byte(data))
+	if err != nil {
+		t.Fatalf("decoding %s: %v", data, err)
+	}
+	return value
+}
@@ -0,0 +1,116 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mayurvarma14/go-movie-review/internals/apperrors"
+	"github.com/mayurvarma14/go-movie-review/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+const (
+	sessionTouchInterval = time.Minute
+	maxDeviceLabelLength = 100
+)
+
+var ErrInvalidSession = errors.New("session is no longer valid")
+
+// NewSession describes a sign-in from the request's device. It expires after
+// ttl, the refresh token lifetime, unless a refresh extends it.
+func NewSession(c *gin.Context, userID, deviceLabel string, ttl time.Duration) *models.Session {
+	now := time.Now()
+	if deviceLabel == "" {
+		deviceLabel = "Unknown device"
+	}
+	if len(deviceLabel) > maxDeviceLabelLength {
+		deviceLabel = deviceLabel[:maxDeviceLabelLength]
+	}
+
+	return &models.Session{
+		ID:          bson.NewObjectID(),
+		UserID:      userID,
+		DeviceLabel: deviceLabel,
+		UserAgent:   c.Request.UserAgent(),
+		IP:          c.ClientIP(),
+		CreatedAt:   now,
+		LastSeenAt:  now,
+		ExpiresAt:   now.Add(ttl),
+	}
+}
+
+func CreateSession(ctx context.Context, session *models.Session, sessionCollection *mongo.Collection) error {
+	if _, err := sessionCollection.InsertOne(ctx, session); err != nil {
+		return fmt.Errorf("creating session: %w", err)
+	}
+	return nil
+}
+
+// TouchSession confirms the session is still active and records activity on
+// it, writing at most once per sessionTouchInterval.
+func TouchSession(ctx context.Context, sessionID, userID string, sessionCollection *mongo.Collection) error {
+	objectID, err := bson.ObjectIDFromHex(sessionID)
+	if err != nil {
+		return apperrors.Unauthorized("invalid session").Wrap(ErrInvalidSession)
+	}
+
+	var session models.Session
+	err = sessionCollection.FindOne(ctx, bson.M{"_id": objectID, "user_id": userID}).Decode(&session)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return apperrors.Unauthorized("session has been revoked").Wrap(ErrInvalidSession)
+		}
+		return apperrors.Internal(fmt.Errorf("finding session: %w", err))
+	}
+
+	now := time.Now()
+	if now.After(session.ExpiresAt) {
+		return apperrors.Unauthorized("session has expired").Wrap(ErrInvalidSession)
+	}
+
+	if now.Sub(session.LastSeenAt) >= sessionTouchInterval {
+		_, err := sessionCollection.UpdateOne(ctx, bson.M{"_id": objectID}, bson.M{"$set": bson.M{"last_seen_at": now}})
+		if err != nil {
+			return apperrors.Internal(fmt.Errorf("updating session: %w", err))
+		}
+	}
+
+	return nil
+}
+
+// RotateSessionRefreshToken swaps the stored refresh token hash for the newly
+// issued one. Presenting a refresh token that was already rotated revokes the
+// whole session, since it means the token was copied. The session is extended
+// by ttl from now.
+func RotateSessionRefreshToken(ctx context.Context, sessionID bson.ObjectID, userID, oldToken, newToken string, ttl time.Duration, sessionCollection *mongo.Collection) error {
+	now := time.Now()
+	filter := bson.M{
+		"_id":                sessionID,
+		"user_id":            userID,
+		"refresh_token_hash": HashToken(oldToken),
+		"expires_at":         bson.M{"$gt": now},
+	}
+	update := bson.M{"$set": bson.M{
+		"refresh_token_hash": HashToken(newToken),
+		"last_seen_at":       now,
+		"expires_at":         now.Add(ttl),
+	}}
+
+	result, err := sessionCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return apperrors.Internal(fmt.Errorf("rotating refresh token: %w", err))
+	}
+
+	if result.MatchedCount == 0 {
+		if _, err := sessionCollection.DeleteOne(ctx, bson.M{"_id": sessionID, "user_id": userID}); err != nil {
+			return apperrors.Internal(fmt.Errorf("revoking session: %w", err))
+		}
+		return apperrors.Unauthorized("refresh token has already been used").Wrap(ErrInvalidSession)
+	}
+
+	return nil
+}
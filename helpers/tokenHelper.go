@@ -1,16 +1,15 @@
 package helpers
 
 import (
-	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
-	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt"
-	"go.mongodb.org/mongo-driver/v2/bson"
-	"go.mongodb.org/mongo-driver/v2/mongo"
-	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"github.com/mayurvarma14/go-movie-review/internals/config"
 )
 
 type JwtSignedDetails struct {
@@ -19,35 +18,89 @@ type JwtSignedDetails struct {
 	Username string
 	Uid      string
 	UserType string
+	// MFAPending marks admin tokens issued while MFA is required but not yet
+	// enrolled; such tokens cannot be used for admin-only actions.
+	MFAPending bool
+	Purpose    string
+	SessionID  string
 	jwt.StandardClaims
 }
 
-var secretKey = []byte(os.Getenv("SECRET_KEY"))
+// TokenManager signs and verifies the JWTs issued by the API. Tokens are
+// signed with secretKey; while a rotation settles, tokens signed with
+// previousKey are still accepted until they expire.
+type TokenManager struct {
+	secretKey       []byte
+	previousKey     []byte
+	accessTokenTTL  time.Duration
+	refreshTokenTTL time.Duration
+	mfaTokenTTL     time.Duration
+}
+
+func NewTokenManager(cfg config.AuthConfig) *TokenManager {
+	return &TokenManager{
+		secretKey:       []byte(cfg.SecretKey),
+		previousKey:     []byte(cfg.PreviousSecretKey),
+		accessTokenTTL:  cfg.AccessTokenTTL,
+		refreshTokenTTL: cfg.RefreshTokenTTL,
+		mfaTokenTTL:     cfg.MFATokenTTL,
+	}
+}
+
+// RefreshTokenTTL is how long a refresh token, and so the session it
+// belongs to, stays valid without being used.
+func (tm *TokenManager) RefreshTokenTTL() time.Duration {
+	return tm.refreshTokenTTL
+}
+
+func (tm *TokenManager) KeyMaterialLoaded() error {
+	if len(tm.secretKey) == 0 {
+		return errors.New("SECRET_KEY is not set")
+	}
+	return nil
+}
+
+// GenerateAllTokens issues an access and refresh token pair. Every refresh
+// token gets a random jti, so a rotation within the same second as the
+// previous one still yields a token with a different hash.
+func (tm *TokenManager) GenerateAllTokens(email, name, userName, userType, uid, sessionID string, mfaPending bool) (string, string, error) {
+	jti, err := newTokenID()
+	if err != nil {
+		return "", "", err
+	}
+	now := time.Now()
 
-func GenerateAllTokens(email, name, userName, userType, uid string) (string, string, error) {
 	claims := &JwtSignedDetails{
-		Email:    email,
-		Name:     name,
-		Username: userName,
-		Uid:      uid,
-		UserType: userType,
+		Email:      email,
+		Name:       name,
+		Username:   userName,
+		Uid:        uid,
+		UserType:   userType,
+		MFAPending: mfaPending,
+		SessionID:  sessionID,
 		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: time.Now().Add(time.Minute * 15).Unix(), // 15 minutes for access token
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(tm.accessTokenTTL).Unix(),
 		},
 	}
 
 	refreshClaims := &JwtSignedDetails{
+		Uid:       uid,
+		Purpose:   RefreshPurpose,
+		SessionID: sessionID,
 		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: time.Now().Add(time.Hour * 24).Unix(), // 24 hours for refresh token
+			Id:        jti,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(tm.refreshTokenTTL).Unix(),
 		},
 	}
 
-	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secretKey)
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(tm.secretKey)
 	if err != nil {
 		return "", "", fmt.Errorf("generating token: %w", err)
 	}
 
-	refreshToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims).SignedString(secretKey)
+	refreshToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims).SignedString(tm.secretKey)
 	if err != nil {
 		return "", "", fmt.Errorf("generating refresh token: %w", err)
 	}
@@ -55,7 +108,91 @@ func GenerateAllTokens(email, name, userName, userType, uid string) (string, str
 	return token, refreshToken, nil
 }
 
-func ValidateToken(signedToken string) (*JwtSignedDetails, error) {
+// GenerateMFAToken issues the token that stands for one MFA challenge. Its
+// jti identifies the challenge when attempts against it are counted.
+func (tm *TokenManager) GenerateMFAToken(uid string) (string, error) {
+	jti, err := newTokenID()
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	claims := &JwtSignedDetails{
+		Uid:     uid,
+		Purpose: MFAChallengePurpose,
+		StandardClaims: jwt.StandardClaims{
+			Id:        jti,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: now.Add(tm.mfaTokenTTL).Unix(),
+		},
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(tm.secretKey)
+	if err != nil {
+		return "", fmt.Errorf("generating MFA token: %w", err)
+	}
+	return token, nil
+}
+
+func (tm *TokenManager) ValidateToken(signedToken string) (*JwtSignedDetails, error) {
+	claims, err := tm.parseToken(signedToken)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Purpose != "" {
+		return nil, errors.New("invalid token")
+	}
+	return claims, nil
+}
+
+func (tm *TokenManager) ValidateMFAToken(signedToken string) (*JwtSignedDetails, error) {
+	claims, err := tm.parseToken(signedToken)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Purpose != MFAChallengePurpose || claims.Id == "" {
+		return nil, errors.New("invalid MFA token")
+	}
+	return claims, nil
+}
+
+func (tm *TokenManager) ValidateRefreshToken(signedToken string) (*JwtSignedDetails, error) {
+	claims, err := tm.parseToken(signedToken)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Purpose != RefreshPurpose {
+		return nil, errors.New("invalid refresh token")
+	}
+	return claims, nil
+}
+
+// newTokenID returns a random jti, which also keeps two tokens issued within
+// the same second from being identical.
+func newTokenID() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", fmt.Errorf("generating token ID: %w", err)
+	}
+	return hex.EncodeToString(id), nil
+}
+
+// HashToken returns the digest under which a refresh token is stored, so a
+// database dump never yields usable credentials.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func (tm *TokenManager) parseToken(signedToken string) (*JwtSignedDetails, error) {
+	claims, err := parseWithKey(signedToken, tm.secretKey)
+	var verr *jwt.ValidationError
+	if err != nil && len(tm.previousKey) > 0 && errors.As(err, &verr) && verr.Errors&jwt.ValidationErrorSignatureInvalid != 0 {
+		claims, err = parseWithKey(signedToken, tm.previousKey)
+	}
+	return claims, err
+}
+
+func parseWithKey(signedToken string, key []byte) (*JwtSignedDetails, error) {
 	token, err := jwt.ParseWithClaims(
 		signedToken,
 		&JwtSignedDetails{},
@@ -63,7 +200,7 @@ func ValidateToken(signedToken string) (*JwtSignedDetails, error) {
 			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 			}
-			return secretKey, nil
+			return key, nil
 		},
 	)
 
@@ -77,24 +214,3 @@ func ValidateToken(signedToken string) (*JwtSignedDetails, error) {
 
 	return nil, errors.New("invalid token")
 }
-
-func UpdateTokens(signedToken, signedRefreshToken, userId string, userCollection *mongo.Collection) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	update := bson.M{
-		"token":         signedToken,
-		"refresh_token": signedRefreshToken,
-		"updated_at":    time.Now(),
-	}
-
-	filter := bson.M{"user_id": userId}
-	opts := options.UpdateOne().SetUpsert(true)
-
-	_, err := userCollection.UpdateOne(ctx, filter, bson.M{"$set": update}, opts)
-	if err != nil {
-		return fmt.Errorf("updating tokens: %w", err)
-	}
-
-	return nil
-}
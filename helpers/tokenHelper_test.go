@@ -0,0 +1,67 @@
+package helpers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mayurvarma14/go-movie-review/internals/config"
+)
+
+func newTestTokenManager(secret, previous string) *TokenManager {
+	return NewTokenManager(config.AuthConfig{
+		SecretKey:         secret,
+		PreviousSecretKey: previous,
+		AccessTokenTTL:    time.Minute,
+		RefreshTokenTTL:   time.Hour,
+		MFATokenTTL:       time.Minute,
+	})
+}
+
+func TestTokensSurviveKeyRotation(t *testing.T) {
+	old := newTestTokenManager("old-key", "")
+	access, refresh, err := old.GenerateAllTokens("a@example.com", "A", "a", "USER", "uid", "sid", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mfa, err := old.GenerateMFAToken("uid")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rotated := newTestTokenManager("new-key", "old-key")
+	if _, err := rotated.ValidateToken(access); err != nil {
+		t.Errorf("access token signed with the previous key: %v", err)
+	}
+	if _, err := rotated.ValidateRefreshToken(refresh); err != nil {
+		t.Errorf("refresh token signed with the previous key: %v", err)
+	}
+	if _, err := rotated.ValidateMFAToken(mfa); err != nil {
+		t.Errorf("MFA token signed with the previous key: %v", err)
+	}
+
+	dropped := newTestTokenManager("new-key", "")
+	if _, err := dropped.ValidateToken(access); err == nil {
+		t.Error("access token signed with a dropped key was accepted")
+	}
+
+	// New tokens are signed with the current key, so they outlive the
+	// previous one being dropped.
+	access, _, err = rotated.GenerateAllTokens("a@example.com", "A", "a", "USER", "uid", "sid", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dropped.ValidateToken(access); err != nil {
+		t.Errorf("token issued after the rotation: %v", err)
+	}
+}
+
+func TestPreviousKeyDoesNotExtendExpiry(t *testing.T) {
+	old := NewTokenManager(config.AuthConfig{SecretKey: "old-key", AccessTokenTTL: -time.Minute, RefreshTokenTTL: time.Hour})
+	access, _, err := old.GenerateAllTokens("a@example.com", "A", "a", "USER", "uid", "sid", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newTestTokenManager("new-key", "old-key").ValidateToken(access); err == nil {
+		t.Error("expired token signed with the previous key was accepted")
+	}
+}
@@ -0,0 +1,70 @@
+package helpers
+
+import (
+	"testing"
+	"time"
+)
+
+// The SHA1 vectors from RFC 6238 Appendix B, cut to the six digits this
+// server uses. The seed is the ASCII string "12345678901234567890".
+func TestTOTPRFC6238(t *testing.T) {
+	key := []byte("12345678901234567890")
+	secret := base32NoPadding.EncodeToString(key)
+
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, tt := range tests {
+		step := tt.unix / totpPeriod
+		if got := totpCode(key, uint64(step)); got != tt.code {
+			t.Errorf("T=%d: totpCode = %s, want %s", tt.unix, got, tt.code)
+		}
+
+		now := time.Unix(tt.unix, 0)
+		matched, ok := validateTOTPAt(secret, tt.code, 0, now)
+		if !ok || matched != step {
+			t.Errorf("T=%d: validateTOTPAt = (%d, %v), want (%d, true)", tt.unix, matched, ok, step)
+		}
+		if _, ok := validateTOTPAt(secret, tt.code, step, now); ok {
+			t.Errorf("T=%d: code accepted again after step %d was used", tt.unix, step)
+		}
+	}
+}
+
+func TestValidateTOTPWindow(t *testing.T) {
+	key := []byte("12345678901234567890")
+	secret := base32NoPadding.EncodeToString(key)
+	now := time.Unix(1111111109, 0)
+	step := now.Unix() / totpPeriod
+
+	tests := []struct {
+		name string
+		code string
+		last int64
+		ok   bool
+	}{
+		{"previous step", totpCode(key, uint64(step-1)), 0, true},
+		{"next step", totpCode(key, uint64(step+1)), 0, true},
+		{"outside skew", totpCode(key, uint64(step-2)), 0, false},
+		{"previous step already used", totpCode(key, uint64(step-1)), step - 1, false},
+		{"later step after earlier use", totpCode(key, uint64(step+1)), step, true},
+		{"wrong length", "12345", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := validateTOTPAt(secret, tt.code, tt.last, now); ok != tt.ok {
+				t.Errorf("validateTOTPAt = %v, want %v", ok, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package helpers
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// NotDeleted narrows filter to documents that are not in the trash. A null
+// match also covers documents written before soft delete existed.
+func NotDeleted(filter bson.M) bson.M {
+	filter["deleted_at"] = nil
+	return filter
+}
+
+// Trashed narrows filter to documents in the trash.
+func Trashed(filter bson.M) bson.M {
+	filter["deleted_at"] = bson.M{"$ne": nil}
+	return filter
+}
+
+// SoftDelete is the update that moves a document to the trash on behalf of
+// the user uid. Like any write it bumps the version.
+func SoftDelete(uid string) bson.M {
+	now := time.Now()
+	return VersionedUpdate(bson.M{"deleted_at": now, "deleted_by": uid, "updated_at": now})
+}
+
+// Restore is the update that takes a document out of the trash.
+func Restore() bson.M {
+	update := VersionedUpdate(bson.M{"updated_at": time.Now()})
+	update["$unset"] = bson.M{"deleted_at": "", "deleted_by": ""}
+	return update
+}
@@ -1,34 +1,426 @@
 package config
 
 import (
-	"log"
+	"encoding"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
 	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
 )
 
-func LoadEnv() {
-	if err := godotenv.Load(".env"); err != nil {
-		log.Println("Warning: Error loading .env file (this is OK in Container):", err)
+// Config is the complete application configuration. Values are resolved in
+// order of increasing precedence: defaults, the YAML file, .env, and finally
+// the process environment. Each field's env tag names the variable that
+// overrides it.
+type Config struct {
+	Server    ServerConfig    `yaml:"server"`
+	Mongo     MongoConfig     `yaml:"mongo"`
+	Auth      AuthConfig      `yaml:"auth"`
+	Password  PasswordConfig  `yaml:"password"`
+	RateLimit RateLimitConfig `yaml:"rate_limit"`
+	CORS      CORSConfig      `yaml:"cors"`
+	Security  SecurityConfig  `yaml:"security"`
+	Log       LogConfig       `yaml:"log"`
+	Tracing   TracingConfig   `yaml:"tracing"`
+	Trash     TrashConfig     `yaml:"trash"`
+}
+
+type ServerConfig struct {
+	Port               int           `yaml:"port" env:"PORT"`
+	RequestTimeout     time.Duration `yaml:"request_timeout" env:"REQUEST_TIMEOUT"`
+	SearchTimeout      time.Duration `yaml:"search_timeout" env:"SEARCH_TIMEOUT"`
+	ExportTimeout      time.Duration `yaml:"export_timeout" env:"EXPORT_TIMEOUT"`
+	ShutdownTimeout    time.Duration `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT"`
+	ShutdownDrainDelay time.Duration `yaml:"shutdown_drain_delay" env:"SHUTDOWN_DRAIN_DELAY"`
+	MaxBodyBytes       int64         `yaml:"max_body_bytes" env:"MAX_BODY_BYTES"`
+	// ImportMaxBodyBytes replaces MaxBodyBytes on the import routes, whose
+	// uploads hold a whole catalog.
+	ImportMaxBodyBytes int64 `yaml:"import_max_body_bytes" env:"IMPORT_MAX_BODY_BYTES"`
+	// StrictJSON rejects request bodies containing fields the endpoint does
+	// not accept instead of silently ignoring them.
+	StrictJSON bool `yaml:"strict_json" env:"STRICT_JSON"`
+	// LegacyRoutes keeps the unversioned paths from before /api/v1 mounted as
+	// deprecated aliases. LegacyRoutesSunset is announced in their Sunset
+	// header.
+	LegacyRoutes       bool      `yaml:"legacy_routes" env:"LEGACY_ROUTES"`
+	LegacyRoutesSunset time.Time `yaml:"legacy_routes_sunset" env:"LEGACY_ROUTES_SUNSET"`
+}
+
+// MongoConfig describes the MongoDB deployment either as a full URI or as
+// structured fields from which one is built. Pool, timeout, concern and TLS
+// settings apply in both cases and take precedence over URI parameters.
+type MongoConfig struct {
+	URI        string `yaml:"uri" env:"MONGO_URI"`
+	User       string `yaml:"user" env:"MONGO_APP_USER"`
+	Password   string `yaml:"password" env:"MONGO_APP_PASSWORD"`
+	Host       string `yaml:"host" env:"MONGO_DOMAIN"`
+	Port       int    `yaml:"port" env:"MONGO_PORT"`
+	SRV        bool   `yaml:"srv" env:"MONGO_SRV"`
+	Database   string `yaml:"database" env:"MONGO_INITDB_DATABASE"`
+	AuthSource string `yaml:"auth_source" env:"MONGO_AUTH_SOURCE"`
+	ReplicaSet string `yaml:"replica_set" env:"MONGO_REPLICA_SET"`
+
+	TLS            bool   `yaml:"tls" env:"MONGO_TLS"`
+	TLSCAFile      string `yaml:"tls_ca_file" env:"MONGO_TLS_CA_FILE"`
+	TLSCertKeyFile string `yaml:"tls_cert_key_file" env:"MONGO_TLS_CERT_KEY_FILE"`
+	TLSInsecure    bool   `yaml:"tls_insecure" env:"MONGO_TLS_INSECURE"`
+
+	MaxPoolSize     int           `yaml:"max_pool_size" env:"MONGO_MAX_POOL_SIZE"`
+	MinPoolSize     int           `yaml:"min_pool_size" env:"MONGO_MIN_POOL_SIZE"`
+	MaxConnIdleTime time.Duration `yaml:"max_conn_idle_time" env:"MONGO_MAX_CONN_IDLE_TIME"`
+	ConnectTimeout  time.Duration `yaml:"connect_timeout" env:"MONGO_CONNECT_TIMEOUT"`
+	Timeout         time.Duration `yaml:"timeout" env:"MONGO_TIMEOUT"`
+
+	ReadPreference string `yaml:"read_preference" env:"MONGO_READ_PREFERENCE"`
+	ReadConcern    string `yaml:"read_concern" env:"MONGO_READ_CONCERN"`
+	WriteConcern   string `yaml:"write_concern" env:"MONGO_WRITE_CONCERN"`
+}
+
+type AuthConfig struct {
+	SecretKey string `yaml:"secret_key" env:"SECRET_KEY"`
+	// PreviousSecretKey is the key SECRET_KEY replaced. Tokens signed with it
+	// are accepted until they expire, so a rotation signs nobody out.
+	PreviousSecretKey string        `yaml:"previous_secret_key" env:"PREVIOUS_SECRET_KEY"`
+	AccessTokenTTL    time.Duration `yaml:"access_token_ttl" env:"ACCESS_TOKEN_TTL"`
+	RefreshTokenTTL   time.Duration `yaml:"refresh_token_ttl" env:"REFRESH_TOKEN_TTL"`
+	MFATokenTTL       time.Duration `yaml:"mfa_token_ttl" env:"MFA_TOKEN_TTL"`
+}
+
+type PasswordConfig struct {
+	MinLength          int  `yaml:"min_length" env:"PASSWORD_MIN_LENGTH"`
+	MaxLength          int  `yaml:"max_length" env:"PASSWORD_MAX_LENGTH"`
+	RequireUpper       bool `yaml:"require_upper" env:"PASSWORD_REQUIRE_UPPER"`
+	RequireLower       bool `yaml:"require_lower" env:"PASSWORD_REQUIRE_LOWER"`
+	RequireDigit       bool `yaml:"require_digit" env:"PASSWORD_REQUIRE_DIGIT"`
+	RequireSymbol      bool `yaml:"require_symbol" env:"PASSWORD_REQUIRE_SYMBOL"`
+	RejectCommon       bool `yaml:"reject_common" env:"PASSWORD_REJECT_COMMON"`
+	RejectPersonalInfo bool `yaml:"reject_personal_info" env:"PASSWORD_REJECT_PERSONAL_INFO"`
+}
+
+// RateLimitConfig holds the allowance for each route group. Clients are
+// identified by user ID when authenticated and by IP otherwise.
+type RateLimitConfig struct {
+	Enabled bool   `yaml:"enabled" env:"RATE_LIMIT_ENABLED"`
+	Store   string `yaml:"store" env:"RATE_LIMIT_STORE"`
+	Default Rate   `yaml:"default" env:"RATE_LIMIT_DEFAULT"`
+	Auth    Rate   `yaml:"auth" env:"RATE_LIMIT_AUTH"`
+	Search  Rate   `yaml:"search" env:"RATE_LIMIT_SEARCH"`
+	Reviews Rate   `yaml:"reviews" env:"RATE_LIMIT_REVIEWS"`
+}
+
+// CORSConfig controls cross-origin access. CORS is disabled while
+// AllowedOrigins is empty; "*" allows any origin but cannot be combined with
+// credentials. List values are comma-separated in the environment.
+type CORSConfig struct {
+	AllowedOrigins   []string      `yaml:"allowed_origins" env:"CORS_ALLOWED_ORIGINS"`
+	AllowedMethods   []string      `yaml:"allowed_methods" env:"CORS_ALLOWED_METHODS"`
+	AllowedHeaders   []string      `yaml:"allowed_headers" env:"CORS_ALLOWED_HEADERS"`
+	ExposedHeaders   []string      `yaml:"exposed_headers" env:"CORS_EXPOSED_HEADERS"`
+	AllowCredentials bool          `yaml:"allow_credentials" env:"CORS_ALLOW_CREDENTIALS"`
+	MaxAge           time.Duration `yaml:"max_age" env:"CORS_MAX_AGE"`
+}
+
+type SecurityConfig struct {
+	// HSTSMaxAge is sent in Strict-Transport-Security on HTTPS requests,
+	// including those a proxy forwarded with X-Forwarded-Proto: https. Zero
+	// disables the header.
+	HSTSMaxAge   time.Duration `yaml:"hsts_max_age" env:"HSTS_MAX_AGE"`
+	FrameOptions string        `yaml:"frame_options" env:"FRAME_OPTIONS"`
+	// MetricsToken, when set, is the bearer token /metrics requires from
+	// scrapers. Empty leaves it open, which suits a private network only.
+	MetricsToken string `yaml:"metrics_token" env:"METRICS_TOKEN"`
+}
+
+type LogConfig struct {
+	Level  string `yaml:"level" env:"LOG_LEVEL"`
+	Format string `yaml:"format" env:"LOG_FORMAT"`
+}
+
+type TracingConfig struct {
+	Exporter    string `yaml:"exporter" env:"OTEL_TRACES_EXPORTER"`
+	ServiceName string `yaml:"service_name" env:"OTEL_SERVICE_NAME"`
+}
+
+// TrashConfig controls how long deleted movies, genres and reviews stay
+// restorable. Zero retention keeps them forever.
+type TrashConfig struct {
+	Retention     time.Duration `yaml:"retention" env:"TRASH_RETENTION"`
+	PurgeInterval time.Duration `yaml:"purge_interval" env:"TRASH_PURGE_INTERVAL"`
+}
+
+// ValidationError lists every problem found while loading the configuration
+// so they can all be fixed in one go.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+const defaultConfigFile = "config.yaml"
+
+func Default() Config {
+	return Config{
+		Server: ServerConfig{
+			Port:               8080,
+			RequestTimeout:     10 * time.Second,
+			SearchTimeout:      5 * time.Second,
+			ExportTimeout:      5 * time.Minute,
+			ShutdownTimeout:    15 * time.Second,
+			MaxBodyBytes:       1 << 20,
+			ImportMaxBodyBytes: 32 << 20,
+			StrictJSON:         true,
+			LegacyRoutes:       true,
+			LegacyRoutesSunset: time.Date(2027, time.April, 30, 0, 0, 0, 0, time.UTC),
+		},
+		Mongo: MongoConfig{
+			Port:           27017,
+			ConnectTimeout: 10 * time.Second,
+			Timeout:        10 * time.Second,
+		},
+		Auth: AuthConfig{
+			AccessTokenTTL:  15 * time.Minute,
+			RefreshTokenTTL: 24 * time.Hour,
+			MFATokenTTL:     5 * time.Minute,
+		},
+		Password: PasswordConfig{
+			MinLength:          8,
+			MaxLength:          128,
+			RequireUpper:       true,
+			RequireLower:       true,
+			RequireDigit:       true,
+			RejectCommon:       true,
+			RejectPersonalInfo: true,
+		},
+		RateLimit: RateLimitConfig{
+			Enabled: true,
+			Store:   "memory",
+			Default: Rate{Requests: 300, Period: time.Minute},
+			Auth:    Rate{Requests: 10, Period: time.Minute},
+			Search:  Rate{Requests: 30, Period: time.Minute},
+			Reviews: Rate{Requests: 20, Period: time.Minute},
+		},
+		CORS: CORSConfig{
+			AllowedMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE"},
+			AllowedHeaders: []string{"Authorization", "Content-Type", "If-Match", "If-None-Match", "X-Request-ID"},
+			ExposedHeaders: []string{"ETag", "Retry-After", "X-Request-ID", "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy", "Deprecation", "Sunset", "Link"},
+			MaxAge:         10 * time.Minute,
+		},
+		Security: SecurityConfig{
+			HSTSMaxAge:   365 * 24 * time.Hour,
+			FrameOptions: "DENY",
+		},
+		Log: LogConfig{
+			Level:  "info",
+			Format: "json",
+		},
+		Tracing: TracingConfig{
+			Exporter:    "none",
+			ServiceName: "go-movie-review",
+		},
+		Trash: TrashConfig{
+			Retention:     30 * 24 * time.Hour,
+			PurgeInterval: time.Hour,
+		},
+	}
+}
+
+// Load reads .env into the environment, then builds and validates the
+// configuration. The YAML file is taken from CONFIG_FILE, or config.yaml in
+// the working directory when that exists.
+func Load() (*Config, error) {
+	if err := godotenv.Load(".env"); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("loading .env: %w", err)
+	}
+
+	cfg := Default()
+
+	path, explicit := os.LookupEnv("CONFIG_FILE")
+	if !explicit {
+		path = defaultConfigFile
+	}
+	if err := cfg.loadFile(path, explicit); err != nil {
+		return nil, err
+	}
+
+	var problems []string
+	problems = append(problems, applyEnv(reflect.ValueOf(&cfg).Elem())...)
+	problems = append(problems, cfg.validate()...)
+	if len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
+	return &cfg, nil
+}
+
+func (c *Config) loadFile(path string, required bool) error {
+	f, err := os.Open(path)
+	if err != nil {
+		if !required && errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("opening config file: %w", err)
+	}
+	defer f.Close()
+
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("parsing config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// applyEnv overrides every field carrying an env tag whose variable is set to
+// a non-empty value, recursing into nested sections.
+func applyEnv(v reflect.Value) []string {
+	var problems []string
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		key := v.Type().Field(i).Tag.Get("env")
+		if key == "" {
+			if field.Kind() == reflect.Struct {
+				problems = append(problems, applyEnv(field)...)
+			}
+			continue
+		}
+		raw := strings.TrimSpace(os.Getenv(key))
+		if raw == "" {
+			continue
+		}
+
+		if u, ok := field.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			if err := u.UnmarshalText([]byte(raw)); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %v", key, err))
+			}
+			continue
+		}
+
+		switch {
+		case field.Type() == reflect.TypeOf(time.Duration(0)):
+			d, err := time.ParseDuration(raw)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %q is not a duration (e.g. 10s, 5m)", key, raw))
+				continue
+			}
+			field.SetInt(int64(d))
+		case field.Kind() == reflect.Int || field.Kind() == reflect.Int64:
+			n, err := strconv.Atoi(raw)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %q is not an integer", key, raw))
+				continue
+			}
+			field.SetInt(int64(n))
+		case field.Kind() == reflect.Bool:
+			b, err := strconv.ParseBool(raw)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %q is not a boolean", key, raw))
+				continue
+			}
+			field.SetBool(b)
+		case field.Kind() == reflect.String:
+			field.SetString(raw)
+		case field.Kind() == reflect.Slice && field.Type().Elem().Kind() == reflect.String:
+			var items []string
+			for _, item := range strings.Split(raw, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					items = append(items, item)
+				}
+			}
+			field.Set(reflect.ValueOf(items))
+		}
 	}
+	return problems
+}
 
-	required := []string{
-		"MONGO_APP_USER",
-		"MONGO_APP_PASSWORD",
-		"MONGO_DOMAIN",
-		"MONGO_INITDB_DATABASE",
-		"MONGO_AUTH_SOURCE",
-		"SECRET_KEY",
+func (c *Config) validate() []string {
+	var problems []string
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			problems = append(problems, fmt.Sprintf(format, args...))
+		}
+	}
+
+	check(c.Server.Port > 0 && c.Server.Port <= 65535, "PORT: must be between 1 and 65535")
+	check(c.Server.RequestTimeout > 0, "REQUEST_TIMEOUT: must be positive")
+	check(c.Server.SearchTimeout > 0, "SEARCH_TIMEOUT: must be positive")
+	check(c.Server.ExportTimeout > 0, "EXPORT_TIMEOUT: must be positive")
+	check(c.Server.ShutdownTimeout > 0, "SHUTDOWN_TIMEOUT: must be positive")
+	check(c.Server.ShutdownDrainDelay >= 0, "SHUTDOWN_DRAIN_DELAY: must not be negative")
+	check(c.Server.MaxBodyBytes > 0, "MAX_BODY_BYTES: must be positive")
+	check(c.Server.ImportMaxBodyBytes > 0, "IMPORT_MAX_BODY_BYTES: must be positive")
+
+	if c.Mongo.URI != "" {
+		check(strings.HasPrefix(c.Mongo.URI, "mongodb://") || strings.HasPrefix(c.Mongo.URI, "mongodb+srv://"),
+			"MONGO_URI: must start with mongodb:// or mongodb+srv://")
+	} else {
+		check(c.Mongo.Host != "", "MONGO_DOMAIN: is required unless MONGO_URI is set")
+		check(c.Mongo.Database != "", "MONGO_INITDB_DATABASE: is required unless MONGO_URI names a database")
+		check((c.Mongo.User == "") == (c.Mongo.Password == ""), "MONGO_APP_USER and MONGO_APP_PASSWORD: must be set together")
+		check(c.Mongo.Port > 0 && c.Mongo.Port <= 65535, "MONGO_PORT: must be between 1 and 65535")
+	}
+	check(c.Mongo.MaxPoolSize >= 0, "MONGO_MAX_POOL_SIZE: must not be negative")
+	check(c.Mongo.MinPoolSize >= 0, "MONGO_MIN_POOL_SIZE: must not be negative")
+	check(c.Mongo.MaxPoolSize == 0 || c.Mongo.MinPoolSize <= c.Mongo.MaxPoolSize, "MONGO_MIN_POOL_SIZE: must not exceed MONGO_MAX_POOL_SIZE")
+	check(c.Mongo.MaxConnIdleTime >= 0, "MONGO_MAX_CONN_IDLE_TIME: must not be negative")
+	check(c.Mongo.ConnectTimeout > 0, "MONGO_CONNECT_TIMEOUT: must be positive")
+	check(c.Mongo.Timeout > 0, "MONGO_TIMEOUT: must be positive")
+	check(c.Mongo.ReadPreference == "" || oneOf(c.Mongo.ReadPreference, "primary", "primaryPreferred", "secondary", "secondaryPreferred", "nearest"),
+		"MONGO_READ_PREFERENCE: must be one of primary, primaryPreferred, secondary, secondaryPreferred, nearest")
+	check(c.Mongo.ReadConcern == "" || oneOf(c.Mongo.ReadConcern, "local", "available", "majority", "linearizable", "snapshot"),
+		"MONGO_READ_CONCERN: must be one of local, available, majority, linearizable, snapshot")
+	if w := c.Mongo.WriteConcern; w != "" && w != "majority" {
+		n, err := strconv.Atoi(w)
+		check(err == nil && n >= 0, "MONGO_WRITE_CONCERN: must be \"majority\" or a non-negative number")
 	}
 
-	var missing []string
-	for _, key := range required {
-		if os.Getenv(key) == "" {
-			missing = append(missing, key)
+	check(c.Auth.SecretKey != "", "SECRET_KEY: is required")
+	check(c.Auth.PreviousSecretKey == "" || c.Auth.PreviousSecretKey != c.Auth.SecretKey, "PREVIOUS_SECRET_KEY: must differ from SECRET_KEY")
+	check(c.Auth.AccessTokenTTL > 0, "ACCESS_TOKEN_TTL: must be positive")
+	check(c.Auth.RefreshTokenTTL > 0, "REFRESH_TOKEN_TTL: must be positive")
+	check(c.Auth.MFATokenTTL > 0, "MFA_TOKEN_TTL: must be positive")
+
+	check(c.Password.MinLength > 0, "PASSWORD_MIN_LENGTH: must be positive")
+	check(c.Password.MaxLength >= c.Password.MinLength, "PASSWORD_MAX_LENGTH: must be at least PASSWORD_MIN_LENGTH")
+
+	check(oneOf(c.RateLimit.Store, "memory", "mongo"), "RATE_LIMIT_STORE: must be one of memory, mongo")
+
+	for _, origin := range c.CORS.AllowedOrigins {
+		if origin == "*" {
+			check(!c.CORS.AllowCredentials, "CORS_ALLOW_CREDENTIALS: cannot be used with a wildcard origin")
+			continue
 		}
+		check(strings.HasPrefix(origin, "http://") || strings.HasPrefix(origin, "https://"),
+			"CORS_ALLOWED_ORIGINS: %q must be an origin such as https://app.example.com", origin)
 	}
+	check(c.CORS.MaxAge >= 0, "CORS_MAX_AGE: must not be negative")
+	check(c.Security.HSTSMaxAge >= 0, "HSTS_MAX_AGE: must not be negative")
+	check(oneOf(c.Security.FrameOptions, "DENY", "SAMEORIGIN"), "FRAME_OPTIONS: must be DENY or SAMEORIGIN")
+
+	check(oneOf(c.Log.Level, "debug", "info", "warn", "error"), "LOG_LEVEL: must be one of debug, info, warn, error")
+	check(oneOf(c.Log.Format, "json", "text"), "LOG_FORMAT: must be one of json, text")
+	check(oneOf(c.Tracing.Exporter, "none", "stdout", "otlp"), "OTEL_TRACES_EXPORTER: must be one of none, stdout, otlp")
+	check(c.Tracing.ServiceName != "", "OTEL_SERVICE_NAME: must not be empty")
 
-	if len(missing) > 0 {
-		log.Fatalf("Missing required environment variables: %v", missing)
+	check(c.Trash.Retention >= 0, "TRASH_RETENTION: must not be negative")
+	check(c.Trash.PurgeInterval > 0, "TRASH_PURGE_INTERVAL: must be positive")
+
+	return problems
+}
+
+func oneOf(value string, allowed ...string) bool {
+	for _, a := range allowed {
+		if strings.EqualFold(value, a) {
+			return true
+		}
 	}
+	return false
 }
@@ -0,0 +1,176 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestLoadPrecedence checks that each source overrides the one before it:
+// defaults, then the YAML file, then .env, then the process environment.
+func TestLoadPrecedence(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "config.yaml"), `
+server:
+  port: 9000
+  request_timeout: 20s
+  export_timeout: 1m
+mongo:
+  host: yaml-host
+  database: movies
+auth:
+  secret_key: from-yaml
+rate_limit:
+  auth: 5/1s
+`)
+	writeFile(t, filepath.Join(dir, ".env"), `
+REQUEST_TIMEOUT=30s
+EXPORT_TIMEOUT=2m
+RATE_LIMIT_AUTH=off
+`)
+	chdir(t, dir)
+	for _, key := range []string{"CONFIG_FILE", "PORT", "REQUEST_TIMEOUT", "SEARCH_TIMEOUT", "EXPORT_TIMEOUT", "RATE_LIMIT_AUTH", "MONGO_URI", "MONGO_DOMAIN", "MONGO_INITDB_DATABASE", "SECRET_KEY"} {
+		unsetenv(t, key)
+	}
+	t.Setenv("EXPORT_TIMEOUT", "3m")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		got, want any
+	}{
+		{"default", cfg.Server.SearchTimeout, 5 * time.Second},
+		{"yaml over default", cfg.Server.Port, 9000},
+		{"yaml over default", cfg.Auth.SecretKey, "from-yaml"},
+		{".env over yaml", cfg.Server.RequestTimeout, 30 * time.Second},
+		{".env over yaml", cfg.RateLimit.Auth, Rate{}},
+		{"env over .env", cfg.Server.ExportTimeout, 3 * time.Minute},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestLoadRejectsUnknownYAMLKeys(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "custom.yaml")
+	writeFile(t, path, "server:\n  prot: 9000\n")
+	chdir(t, dir)
+	t.Setenv("CONFIG_FILE", path)
+
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "prot") {
+		t.Fatalf("got %v, want an error naming the unknown key", err)
+	}
+}
+
+func TestApplyEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		check   func(Config) any
+		want    any
+		problem string
+	}{
+		{
+			name:  "duration",
+			env:   map[string]string{"SEARCH_TIMEOUT": "750ms"},
+			check: func(c Config) any { return c.Server.SearchTimeout },
+			want:  750 * time.Millisecond,
+		},
+		{
+			name:  "integer",
+			env:   map[string]string{"MAX_BODY_BYTES": "2048"},
+			check: func(c Config) any { return c.Server.MaxBodyBytes },
+			want:  int64(2048),
+		},
+		{
+			name:  "boolean",
+			env:   map[string]string{"STRICT_JSON": "false"},
+			check: func(c Config) any { return c.Server.StrictJSON },
+			want:  false,
+		},
+		{
+			name:  "list trims and drops empty items",
+			env:   map[string]string{"CORS_ALLOWED_ORIGINS": " https://a.example , ,https://b.example"},
+			check: func(c Config) any { return c.CORS.AllowedOrigins },
+			want:  []string{"https://a.example", "https://b.example"},
+		},
+		{
+			name:  "text unmarshaler",
+			env:   map[string]string{"RATE_LIMIT_SEARCH": "10/1s"},
+			check: func(c Config) any { return c.RateLimit.Search },
+			want:  Rate{Requests: 10, Period: time.Second},
+		},
+		{
+			name:  "blank value keeps the default",
+			env:   map[string]string{"PORT": "  "},
+			check: func(c Config) any { return c.Server.Port },
+			want:  8080,
+		},
+		{name: "bad duration", env: map[string]string{"SEARCH_TIMEOUT": "5"}, problem: "SEARCH_TIMEOUT"},
+		{name: "bad integer", env: map[string]string{"PORT": "http"}, problem: "PORT"},
+		{name: "bad boolean", env: map[string]string{"STRICT_JSON": "sometimes"}, problem: "STRICT_JSON"},
+		{name: "bad rate", env: map[string]string{"RATE_LIMIT_AUTH": "lots"}, problem: "RATE_LIMIT_AUTH"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			cfg := Default()
+			problems := applyEnv(reflect.ValueOf(&cfg).Elem())
+
+			if tt.problem != "" {
+				if len(problems) != 1 || !strings.HasPrefix(problems[0], tt.problem+":") {
+					t.Fatalf("got problems %q, want one for %s", problems, tt.problem)
+				}
+				return
+			}
+			if len(problems) > 0 {
+				t.Fatalf("unexpected problems: %q", problems)
+			}
+			if got := tt.check(cfg); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// chdir moves into dir for the rest of the test, since Load reads .env from
+// the working directory.
+func chdir(t *testing.T, dir string) {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+}
+
+// unsetenv removes key for the rest of the test. Load's .env only fills in
+// variables that are not already set, even to an empty value.
+func unsetenv(t *testing.T, key string) {
+	t.Helper()
+	t.Setenv(key, "")
+	os.Unsetenv(key)
+}
@@ -0,0 +1,54 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Rate is a request allowance written as "<requests>/<period>", e.g. "60/1m"
+// or "5/1s". "off" disables the limit.
+type Rate struct {
+	Requests int
+	Period   time.Duration
+}
+
+func (r Rate) Disabled() bool {
+	return r.Requests <= 0 || r.Period <= 0
+}
+
+func (r Rate) String() string {
+	if r.Disabled() {
+		return "off"
+	}
+	return fmt.Sprintf("%d/%s", r.Requests, r.Period)
+}
+
+func (r *Rate) UnmarshalText(text []byte) error {
+	value := strings.TrimSpace(string(text))
+	if strings.EqualFold(value, "off") {
+		*r = Rate{}
+		return nil
+	}
+
+	count, period, ok := strings.Cut(value, "/")
+	if !ok {
+		return fmt.Errorf("%q is not a rate (e.g. 60/1m or off)", value)
+	}
+	requests, err := strconv.Atoi(count)
+	if err != nil || requests <= 0 {
+		return fmt.Errorf("%q: request count must be a positive integer", value)
+	}
+	d, err := time.ParseDuration(period)
+	if err != nil || d <= 0 {
+		return fmt.Errorf("%q: period must be a positive duration", value)
+	}
+
+	*r = Rate{Requests: requests, Period: d}
+	return nil
+}
+
+func (r Rate) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateUnmarshalText(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Rate
+		wantErr bool
+	}{
+		{in: "60/1m", want: Rate{Requests: 60, Period: time.Minute}},
+		{in: " 5/1s ", want: Rate{Requests: 5, Period: time.Second}},
+		{in: "100/1h30m", want: Rate{Requests: 100, Period: 90 * time.Minute}},
+		{in: "off", want: Rate{}},
+		{in: "OFF", want: Rate{}},
+		{in: "", wantErr: true},
+		{in: "60", wantErr: true},
+		{in: "0/1m", wantErr: true},
+		{in: "-1/1m", wantErr: true},
+		{in: "x/1m", wantErr: true},
+		{in: "60/", wantErr: true},
+		{in: "60/0s", wantErr: true},
+		{in: "60/-1m", wantErr: true},
+		{in: "60/minute", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got := Rate{Requests: 1, Period: time.Hour}
+			err := got.UnmarshalText([]byte(tt.in))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("got %v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRateTextRoundTrip(t *testing.T) {
+	for _, r := range []Rate{{}, {Requests: 60, Period: time.Minute}, {Requests: 5, Period: 1500 * time.Millisecond}} {
+		text, _ := r.MarshalText()
+		var got Rate
+		if err := got.UnmarshalText(text); err != nil {
+			t.Fatalf("%s: %v", text, err)
+		}
+		if got != r {
+			t.Errorf("%s: got %+v, want %+v", text, got, r)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package server
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/mayurvarma14/go-movie-review/controllers"
+	"github.com/mayurvarma14/go-movie-review/database"
+	"github.com/mayurvarma14/go-movie-review/internals/config"
+	"github.com/mayurvarma14/go-movie-review/internals/ratelimit"
+	"github.com/mayurvarma14/go-movie-review/middleware"
+	"github.com/mayurvarma14/go-movie-review/routes"
+)
+
+// NewRouter builds the handler Run serves: the global middleware and every
+// route, with controllers on db. hc is passed in so the caller can mark it
+// draining during shutdown. Nothing here talks to the database, so tests can
+// build the router on a client that never connects.
+func NewRouter(cfg *config.Config, db *database.Database, hc *controllers.HealthController) *gin.Engine {
+	auth := middleware.NewAuthenticator(db, cfg)
+	limiter := middleware.NewRateLimiter(newRateLimitStore(db, cfg.RateLimit), cfg.RateLimit)
+
+	binding.EnableDecoderDisallowUnknownFields = cfg.Server.StrictJSON
+
+	router := gin.New()
+	router.Use(middleware.RequestID())
+	router.Use(middleware.Tracing(cfg.Tracing.ServiceName))
+	router.Use(middleware.Logger())
+	router.Use(middleware.Metrics())
+	// ErrorHandler wraps Recovery so a recovered panic still gets its 500.
+	router.Use(middleware.ErrorHandler())
+	router.Use(middleware.Recovery())
+	router.Use(middleware.SecurityHeaders(cfg.Security))
+	router.Use(middleware.CORS(cfg.CORS))
+	router.Use(middleware.BodyLimit(cfg.Server.MaxBodyBytes))
+	router.Use(middleware.Timeout(cfg.Server.RequestTimeout))
+
+	routes.HealthRoutes(router, hc)
+	routes.MetricsRoutes(router, cfg.Security.MetricsToken)
+	routes.APIRoutes(router, routes.Handlers{
+		Users:              controllers.NewUserController(db, cfg),
+		Sessions:           controllers.NewSessionController(db),
+		Genres:             controllers.NewGenreController(db),
+		Movies:             controllers.NewMovieController(db),
+		Reviews:            controllers.NewReviewController(db),
+		Audit:              controllers.NewAuditController(db),
+		Auth:               auth,
+		Limiter:            limiter,
+		SearchTimeout:      cfg.Server.SearchTimeout,
+		ExportTimeout:      cfg.Server.ExportTimeout,
+		ImportMaxBodyBytes: cfg.Server.ImportMaxBodyBytes,
+	}, cfg.Server)
+	routes.DocsRoutes(router)
+
+	return router
+}
+
+func newRateLimitStore(db *database.Database, cfg config.RateLimitConfig) ratelimit.Store {
+	if cfg.Store == "mongo" {
+		return ratelimit.NewMongoStore(db.OpenCollection("rate_limit"))
+	}
+	return ratelimit.NewMemoryStore()
+}
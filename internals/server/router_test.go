@@ -0,0 +1,50 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mayurvarma14/go-movie-review/controllers"
+	"github.com/mayurvarma14/go-movie-review/database"
+	"github.com/mayurvarma14/go-movie-review/internals/config"
+	"github.com/mayurvarma14/go-movie-review/middleware"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func TestPanicReturnsProblem(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	client, err := mongo.Connect(options.Client().ApplyURI("mongodb://127.0.0.1:1"))
+	if err != nil {
+		t.Fatalf("creating mongo client: %v", err)
+	}
+	db := &database.Database{Client: client, Name: "test"}
+	cfg := config.Default()
+	cfg.Auth.SecretKey = "test-secret"
+
+	router := NewRouter(&cfg, db, controllers.NewHealthController(db, &cfg))
+	router.GET("/panic", func(c *gin.Context) { panic("boom") })
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != middleware.ProblemContentType {
+		t.Errorf("Content-Type = %q, want %q", ct, middleware.ProblemContentType)
+	}
+	var problem middleware.Problem
+	if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("decoding body %q: %v", w.Body.String(), err)
+	}
+	if problem.Status != http.StatusInternalServerError || problem.Code != "internal_error" || problem.RequestID == "" {
+		t.Errorf("problem = %+v, want a 500 internal_error with the request ID", problem)
+	}
+	if problem.Detail == "boom" {
+		t.Error("panic value leaked into the response")
+	}
+}
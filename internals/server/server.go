@@ -0,0 +1,86 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/mayurvarma14/go-movie-review/controllers"
+	"github.com/mayurvarma14/go-movie-review/database"
+	"github.com/mayurvarma14/go-movie-review/docs"
+	"github.com/mayurvarma14/go-movie-review/helpers"
+	"github.com/mayurvarma14/go-movie-review/internals/config"
+	"github.com/mayurvarma14/go-movie-review/internals/tracing"
+	"github.com/mayurvarma14/go-movie-review/routes"
+)
+
+// Run connects to MongoDB, applies pending migrations and serves the API
+// until ctx is done, then drains in-flight requests and shuts down. It
+// returns an error if the server could not start or did not stop cleanly.
+func Run(ctx context.Context, cfg *config.Config) error {
+	shutdownTracing, err := tracing.Setup(ctx, cfg.Tracing)
+	if err != nil {
+		return fmt.Errorf("tracing init failed: %w", err)
+	}
+
+	db, err := database.New(ctx, cfg.Mongo)
+	if err != nil {
+		return fmt.Errorf("database init failed: %w", err)
+	}
+
+	if err := db.Migrate(ctx); err != nil {
+		return fmt.Errorf("database migration failed: %w", err)
+	}
+
+	hc := controllers.NewHealthController(db, cfg)
+	router := NewRouter(cfg, db, hc)
+	if err := docs.CheckCoverage(routes.Documented(router.Routes())); err != nil {
+		return fmt.Errorf("API documentation check failed: %w", err)
+	}
+
+	go db.RunTrashPurger(ctx, cfg.Trash.Retention, cfg.Trash.PurgeInterval, helpers.PurgeAuditor(helpers.SystemActor, db.OpenCollection("audit")))
+
+	srv := &http.Server{
+		Addr:              fmt.Sprintf(":%d", cfg.Server.Port),
+		Handler:           router,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		slog.Info("server listening", "port", cfg.Server.Port)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+	}()
+
+	var runErr error
+	select {
+	case err := <-serverErr:
+		runErr = fmt.Errorf("failed to start server: %w", err)
+	case <-ctx.Done():
+		slog.Info("shutdown signal received, draining in-flight requests")
+	}
+
+	hc.SetDraining()
+	time.Sleep(cfg.Server.ShutdownDrainDelay)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		runErr = errors.Join(runErr, fmt.Errorf("graceful shutdown did not complete: %w", err))
+	}
+	if err := db.Client.Disconnect(shutdownCtx); err != nil {
+		runErr = errors.Join(runErr, fmt.Errorf("failed to disconnect from MongoDB: %w", err))
+	}
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		runErr = errors.Join(runErr, fmt.Errorf("failed to flush traces: %w", err))
+	}
+
+	slog.Info("server stopped")
+	return runErr
+}
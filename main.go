@@ -2,56 +2,32 @@ package main
 
 import (
 	"context"
-	"log"
-	"net/http"
+	"fmt"
+	"log/slog"
 	"os"
+	"os/signal"
+	"syscall"
 
-	"github.com/gin-gonic/gin"
-	"github.com/mayurvarma14/go-movie-review/controllers"
-	"github.com/mayurvarma14/go-movie-review/database"
 	"github.com/mayurvarma14/go-movie-review/internals/config"
-	"github.com/mayurvarma14/go-movie-review/routes"
+	"github.com/mayurvarma14/go-movie-review/internals/logger"
+	"github.com/mayurvarma14/go-movie-review/internals/server"
 )
 
 func main() {
-	config.LoadEnv()
-
-	ctx := context.Background()
-	db, err := database.New(ctx)
+	cfg, err := config.Load()
 	if err != nil {
-		log.Fatal("Database init failed:", err)
-	}
-	defer func() {
-		if err := db.Client.Disconnect(ctx); err != nil {
-			log.Fatal("Failed to disconnect from MongoDB:", err)
-		}
-	}()
-
-	uc := controllers.NewUserController(db)
-	gc := controllers.NewGenreController(db)
-	mc := controllers.NewMovieController(db)
-	rc := controllers.NewReviewController(db)
-
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
 	}
+	logger.Setup(cfg.Log)
 
-	router := gin.Default()
-	router.Use(gin.Logger())
-
-	routes.AuthRoutes(router, uc)
-	routes.UserRoutes(router, uc)
-	routes.GenreRoutes(router, gc)
-	routes.MovieRoutes(router, mc)
-	routes.ReviewRoutes(router, rc)
-
-	router.GET("/api", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"message": "Welcome to the movie review API"})
-	})
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	// A second signal during shutdown kills the process.
+	context.AfterFunc(ctx, stop)
 
-	log.Printf("Server listening on port %s", port)
-	if err := router.Run(":" + port); err != nil {
-		log.Fatal("Failed to start server:", err)
+	if err := server.Run(ctx, cfg); err != nil {
+		slog.Error("server failed", "error", err)
+		os.Exit(1)
 	}
 }
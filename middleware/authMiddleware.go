@@ -2,49 +2,99 @@ package middleware
 
 import (
 	"errors"
-	"fmt"
-	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/mayurvarma14/go-movie-review/database"
 	"github.com/mayurvarma14/go-movie-review/helpers"
+	"github.com/mayurvarma14/go-movie-review/internals/apperrors"
+	"github.com/mayurvarma14/go-movie-review/internals/config"
+	"github.com/mayurvarma14/go-movie-review/internals/metrics"
+	"github.com/mayurvarma14/go-movie-review/internals/tracing"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 )
 
-func AuthenticateUser() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		authHeader := c.Request.Header.Get("Authorization")
-		if authHeader == "" {
-			helpers.HandleError(c, http.StatusUnauthorized, errors.New("no authorization header provided"))
-			c.Abort()
-			return
-		}
+type Authenticator struct {
+	sessionCollection *mongo.Collection
+	tokens            *helpers.TokenManager
+}
 
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
-			helpers.HandleError(c, http.StatusUnauthorized, errors.New("invalid authorization header format. Expected 'Bearer <token>'"))
-			c.Abort()
-			return
-		}
+func NewAuthenticator(db *database.Database, cfg *config.Config) *Authenticator {
+	return &Authenticator{
+		sessionCollection: db.Client.Database(db.Name).Collection("session"),
+		tokens:            helpers.NewTokenManager(cfg.Auth),
+	}
+}
 
-		clientToken := parts[1]
-		if clientToken == "" {
-			helpers.HandleError(c, http.StatusUnauthorized, errors.New("no authorization header provided"))
-			c.Abort()
-			return
-		}
+func (a *Authenticator) AuthenticateUser() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// The span covers token validation and the session lookup only, so
+		// the request context is restored before the rest of the chain runs.
+		request := c.Request
+		ctx, span := tracing.Tracer().Start(request.Context(), "auth.authenticate")
+		c.Request = request.WithContext(ctx)
+		claims, err := a.authenticate(c)
+		c.Request = request
 
-		claims, err := helpers.ValidateToken(clientToken)
 		if err != nil {
-			helpers.HandleError(c, http.StatusUnauthorized, fmt.Errorf("validating token: %w", err))
-			c.Abort()
+			span.SetStatus(codes.Error, err.Error())
+			span.End()
+			helpers.HandleError(c, err)
 			return
 		}
+		span.SetAttributes(
+			attribute.String("enduser.id", claims.Uid),
+			attribute.String("enduser.role", claims.UserType),
+		)
+		span.End()
 
 		c.Set("email", claims.Email)
 		c.Set("name", claims.Name)
 		c.Set("username", claims.Username)
 		c.Set("uid", claims.Uid)
 		c.Set("user_type", claims.UserType)
+		c.Set("mfa_pending", claims.MFAPending)
+		c.Set("session_id", claims.SessionID)
 		c.Next()
 	}
 }
+
+func (a *Authenticator) authenticate(c *gin.Context) (*helpers.JwtSignedDetails, error) {
+	authHeader := c.Request.Header.Get("Authorization")
+	if authHeader == "" {
+		metrics.TokenValidationFailures.WithLabelValues("missing_header").Inc()
+		return nil, apperrors.Unauthorized("no authorization header provided")
+	}
+
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
+		metrics.TokenValidationFailures.WithLabelValues("malformed_header").Inc()
+		return nil, apperrors.Unauthorized("invalid authorization header format. Expected 'Bearer <token>'")
+	}
+
+	clientToken := parts[1]
+	if clientToken == "" {
+		metrics.TokenValidationFailures.WithLabelValues("missing_header").Inc()
+		return nil, apperrors.Unauthorized("no authorization header provided")
+	}
+
+	claims, err := a.tokens.ValidateToken(clientToken)
+	if err != nil {
+		metrics.TokenValidationFailures.WithLabelValues("invalid_token").Inc()
+		return nil, apperrors.Unauthorized("invalid or expired token").Wrap(err)
+	}
+
+	ctx, cancel := helpers.RequestContext(c)
+	defer cancel()
+
+	if err := helpers.TouchSession(ctx, claims.SessionID, claims.Uid, a.sessionCollection); err != nil {
+		if errors.Is(err, helpers.ErrInvalidSession) {
+			metrics.TokenValidationFailures.WithLabelValues("invalid_session").Inc()
+		}
+		return nil, err
+	}
+
+	return claims, nil
+}
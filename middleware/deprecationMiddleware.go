@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Deprecated marks responses from routes kept only for backwards
+// compatibility. Deprecation (RFC 9745) carries the date the route was
+// deprecated, Sunset (RFC 8594) the date it goes away when one is set, and
+// Link points clients at the same path under successorPrefix.
+func Deprecated(since, sunset time.Time, successorPrefix string) gin.HandlerFunc {
+	deprecation := fmt.Sprintf("@%d", since.Unix())
+	sunsetDate := ""
+	if !sunset.IsZero() {
+		sunsetDate = sunset.UTC().Format(http.TimeFormat)
+	}
+
+	return func(c *gin.Context) {
+		header := c.Writer.Header()
+		header.Set("Deprecation", deprecation)
+		if sunsetDate != "" {
+			header.Set("Sunset", sunsetDate)
+		}
+		header.Add("Link", fmt.Sprintf(`<%s%s>; rel="successor-version"`, successorPrefix, c.Request.URL.Path))
+		c.Next()
+	}
+}
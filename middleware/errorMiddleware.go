@@ -0,0 +1,190 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/mayurvarma14/go-movie-review/helpers"
+	"github.com/mayurvarma14/go-movie-review/internals/apperrors"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+const ProblemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem details body. Code is a stable,
+// machine-readable identifier clients can switch on instead of parsing Detail.
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	Code      string       `json:"code"`
+	RequestID string       `json:"request_id,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+var kindStatus = map[apperrors.Kind]int{
+	apperrors.KindInternal:             http.StatusInternalServerError,
+	apperrors.KindValidation:           http.StatusBadRequest,
+	apperrors.KindUnauthorized:         http.StatusUnauthorized,
+	apperrors.KindForbidden:            http.StatusForbidden,
+	apperrors.KindNotFound:             http.StatusNotFound,
+	apperrors.KindConflict:             http.StatusConflict,
+	apperrors.KindTooManyRequests:      http.StatusTooManyRequests,
+	apperrors.KindUnsupportedMediaType: http.StatusUnsupportedMediaType,
+	apperrors.KindPreconditionFailed:   http.StatusPreconditionFailed,
+}
+
+var kindCode = map[apperrors.Kind]string{
+	apperrors.KindInternal:             "internal_error",
+	apperrors.KindValidation:           "bad_request",
+	apperrors.KindUnauthorized:         "unauthorized",
+	apperrors.KindForbidden:            "forbidden",
+	apperrors.KindNotFound:             "not_found",
+	apperrors.KindConflict:             "conflict",
+	apperrors.KindTooManyRequests:      "rate_limited",
+	apperrors.KindUnsupportedMediaType: "unsupported_media_type",
+	apperrors.KindPreconditionFailed:   "precondition_failed",
+}
+
+// ErrorHandler turns the last error recorded with helpers.HandleError into a
+// problem response. Internal errors are logged with the request ID and
+// replaced by a generic message so driver and database details never reach
+// clients.
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		err := c.Errors.Last().Err
+		problem := newProblem(c, err)
+
+		if problem.Status >= http.StatusInternalServerError {
+			slog.ErrorContext(c.Request.Context(), "request failed",
+				"method", c.Request.Method,
+				"path", c.Request.URL.Path,
+				"status", problem.Status,
+				"error", err,
+			)
+		}
+
+		c.Header("Content-Type", ProblemContentType)
+		c.JSON(problem.Status, problem)
+	}
+}
+
+func newProblem(c *gin.Context, err error) Problem {
+	kind := apperrors.KindOf(err)
+	message := "internal server error"
+
+	var appErr *apperrors.Error
+	if errors.As(err, &appErr) {
+		message = appErr.Message
+	}
+	if kind == apperrors.KindInternal && mongo.IsDuplicateKeyError(err) {
+		kind = apperrors.KindConflict
+		message = "resource already exists"
+	}
+
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return Problem{
+			Type:      "about:blank",
+			Title:     http.StatusText(http.StatusRequestEntityTooLarge),
+			Status:    http.StatusRequestEntityTooLarge,
+			Detail:    fmt.Sprintf("request body must not exceed %d bytes", tooLarge.Limit),
+			Instance:  c.Request.URL.Path,
+			Code:      "body_too_large",
+			RequestID: c.GetString("request_id"),
+		}
+	}
+
+	status := kindStatus[kind]
+	problem := Problem{
+		Type:      "about:blank",
+		Title:     http.StatusText(status),
+		Status:    status,
+		Detail:    message,
+		Instance:  c.Request.URL.Path,
+		Code:      kindCode[kind],
+		RequestID: c.GetString("request_id"),
+	}
+
+	if kind == apperrors.KindValidation {
+		addFieldErrors(&problem, err)
+	}
+
+	return problem
+}
+
+func addFieldErrors(problem *Problem, err error) {
+	var validationErrs validator.ValidationErrors
+	var policyErr *helpers.PasswordPolicyError
+	var typeErr *json.UnmarshalTypeError
+	var syntaxErr *json.SyntaxError
+	switch {
+	case errors.As(err, &validationErrs):
+		problem.Code = "validation_failed"
+		problem.Detail = "request failed validation"
+		for _, fe := range validationErrs {
+			problem.Errors = append(problem.Errors, FieldError{
+				Field:   fe.Field(),
+				Rule:    fe.Tag(),
+				Param:   fe.Param(),
+				Message: helpers.ValidationMessage(fe),
+			})
+		}
+	case errors.As(err, &policyErr):
+		problem.Code = "password_policy"
+		problem.Detail = "password does not meet policy"
+		for _, v := range policyErr.Violations {
+			problem.Errors = append(problem.Errors, FieldError{Field: "password", Rule: v.Rule, Message: v.Message})
+		}
+	case errors.As(err, &typeErr):
+		problem.Code = "invalid_body"
+		problem.Detail = "request body has a field of the wrong type"
+		problem.Errors = []FieldError{{
+			Field:   typeErr.Field,
+			Rule:    "type",
+			Param:   typeErr.Type.String(),
+			Message: fmt.Sprintf("must be of type %s", typeErr.Type),
+		}}
+	case errors.As(err, &syntaxErr):
+		problem.Code = "invalid_body"
+		problem.Detail = "request body is not valid JSON"
+	default:
+		if field, ok := unknownField(err); ok {
+			problem.Code = "invalid_body"
+			problem.Detail = "request body has a field this endpoint does not accept"
+			problem.Errors = []FieldError{{Field: field, Rule: "unknown", Message: "is not a recognized field"}}
+		}
+	}
+}
+
+// unknownField extracts the field name from the untyped error encoding/json
+// returns when DisallowUnknownFields rejects a body.
+func unknownField(err error) (string, bool) {
+	for ; err != nil; err = errors.Unwrap(err) {
+		if name, ok := strings.CutPrefix(err.Error(), `json: unknown field "`); ok {
+			return strings.TrimSuffix(name, `"`), true
+		}
+	}
+	return "", false
+}
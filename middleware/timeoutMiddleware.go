@@ -0,0 +1,16 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Timeout sets the deadline helpers.RequestContext applies to the handlers
+// after it. Registering it on a single route overrides the global value.
+func Timeout(timeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set("request_timeout", timeout)
+		c.Next()
+	}
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// AuditEntry records one administrative write. Entries are only ever
+// inserted; before and after hold the stored document on either side of the
+// write, and before is absent for creations.
+type AuditEntry struct {
+	ID         bson.ObjectID `bson:"_id"`
+	ActorID    string        `bson:"actor_id"`
+	Action     string        `bson:"action"`
+	Resource   string        `bson:"resource"`
+	ResourceID string        `bson:"resource_id"`
+	Before     bson.Raw      `bson:"before,omitempty"`
+	After      bson.Raw      `bson:"after,omitempty"`
+	IP         string        `bson:"ip"`
+	RequestID  string        `bson:"request_id"`
+	CreatedAt  time.Time     `bson:"created_at"`
+}
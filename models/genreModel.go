@@ -8,8 +8,11 @@ import (
 
 type Genre struct {
 	ID        bson.ObjectID `bson:"_id"`
-	Name      *string       `json:"name" validate:"required,min=4,max=100"`
-	CreatedAt time.Time     `json:"created_at" bson:"created_at"`
-	UpdatedAt time.Time     `json:"updated_at" bson:"updated_at"`
-	GenreID   int           `json:"genre_id" validate:"required" bson:"genre_id"`
+	Name      *string       `bson:"name"`
+	CreatedAt time.Time     `bson:"created_at"`
+	UpdatedAt time.Time     `bson:"updated_at"`
+	Version   int64         `bson:"version"`
+	DeletedAt *time.Time    `bson:"deleted_at,omitempty"`
+	DeletedBy string        `bson:"deleted_by,omitempty"`
+	GenreID   int           `bson:"genre_id"`
 }
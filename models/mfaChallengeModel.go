@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// MFAChallenge counts the attempts made with one mfa_token, keyed by its jti,
+// so a challenge cannot be guessed at for the token's whole lifetime.
+type MFAChallenge struct {
+	ID        string    `bson:"_id"`
+	Attempts  int       `bson:"attempts"`
+	ExpiresAt time.Time `bson:"expires_at"`
+}
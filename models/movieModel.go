@@ -7,12 +7,16 @@ import (
 )
 
 type Movie struct {
-	ID        bson.ObjectID `bson:"_id"`
-	Name      *string       `json:"name" validate:"required"`
-	Topic     *string       `json:"topic" validate:"required"`
-	GenreID   int           `json:"genre_id" bson:"genre_id"`
-	MovieURL  *string       `json:"movie_url" validate:"required"`
-	MovieID   int           `json:"movie_id" bson:"movie_id"`
-	CreatedAt time.Time     `json:"created_at" bson:"created_at"`
-	UpdatedAt time.Time     `json:"updated_at" bson:"updated_at"`
+	ID          bson.ObjectID `bson:"_id"`
+	Name        *string       `bson:"name"`
+	Topic       *string       `bson:"topic"`
+	GenreID     int           `bson:"genre_id"`
+	MovieURL    *string       `bson:"movie_url"`
+	MovieID     int           `bson:"movie_id"`
+	ReviewCount int64         `bson:"review_count"`
+	CreatedAt   time.Time     `bson:"created_at"`
+	UpdatedAt   time.Time     `bson:"updated_at"`
+	Version     int64         `bson:"version"`
+	DeletedAt   *time.Time    `bson:"deleted_at,omitempty"`
+	DeletedBy   string        `bson:"deleted_by,omitempty"`
 }
@@ -8,9 +8,12 @@ import (
 
 type Reviews struct {
 	ID         bson.ObjectID `bson:"_id"`
-	MovieID    int           `json:"movie_id" bson:"movie_id"`
-	ReviewerID bson.ObjectID `json:"reviewer_id" bson:"reviewer_id"`
-	Review     *string       `json:"review" validate:"required"`
-	CreatedAt  time.Time     `json:"created_at" bson:"created_at"`
-	UpdatedAt  time.Time     `json:"updated_at" bson:"updated_at"`
+	MovieID    int           `bson:"movie_id"`
+	ReviewerID bson.ObjectID `bson:"reviewer_id"`
+	Review     *string       `bson:"review"`
+	CreatedAt  time.Time     `bson:"created_at"`
+	UpdatedAt  time.Time     `bson:"updated_at"`
+	Version    int64         `bson:"version"`
+	DeletedAt  *time.Time    `bson:"deleted_at,omitempty"`
+	DeletedBy  string        `bson:"deleted_by,omitempty"`
 }
@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type Session struct {
+	ID          bson.ObjectID `bson:"_id"`
+	UserID      string        `bson:"user_id"`
+	DeviceLabel string        `bson:"device_label"`
+	UserAgent   string        `bson:"user_agent"`
+	IP          string        `bson:"ip"`
+	CreatedAt   time.Time     `bson:"created_at"`
+	LastSeenAt  time.Time     `bson:"last_seen_at"`
+	ExpiresAt   time.Time     `bson:"expires_at"`
+
+	RefreshTokenHash string `bson:"refresh_token_hash"`
+}
@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+const SecuritySettingsID = "security"
+
+type SecuritySettings struct {
+	ID              string    `bson:"_id"`
+	RequireAdminMFA bool      `bson:"require_admin_mfa"`
+	UpdatedAt       time.Time `bson:"updated_at"`
+}
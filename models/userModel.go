@@ -7,15 +7,19 @@ import (
 )
 
 type User struct {
-	ID           bson.ObjectID `bson:"_id"`
-	Name         *string       `json:"name" validate:"required,min=4,max=100"`
-	Username     *string       `json:"username" validate:"required,min=4,max=100"`
-	Password     *string       `json:"password" validate:"required,min=8"`
-	Email        *string       `json:"email" validate:"email,required"`
-	Token        *string       `json:"token,omitempty" bson:"token,omitempty"`
-	UserType     *string       `json:"user_type" validate:"required,eq=ADMIN|eq=USER"`
-	RefreshToken *string       `json:"refresh_token,omitempty" bson:"refresh_token,omitempty"`
-	CreatedAt    time.Time     `json:"created_at" bson:"created_at"`
-	UpdatedAt    time.Time     `json:"updated_at" bson:"updated_at"`
-	UserID       string        `json:"user_id" bson:"user_id"`
+	ID        bson.ObjectID `bson:"_id"`
+	Name      *string       `bson:"name"`
+	Username  *string       `bson:"username"`
+	Password  *string       `bson:"password"`
+	Email     *string       `bson:"email"`
+	UserType  *string       `bson:"user_type"`
+	CreatedAt time.Time     `bson:"created_at"`
+	UpdatedAt time.Time     `bson:"updated_at"`
+	UserID    string        `bson:"user_id"`
+
+	MFAEnabled       bool     `bson:"mfa_enabled"`
+	MFASecret        *string  `bson:"mfa_secret,omitempty"`
+	MFALastStep      int64    `bson:"mfa_last_step,omitempty"`
+	MFAPendingSecret *string  `bson:"mfa_pending_secret,omitempty"`
+	RecoveryCodes    []string `bson:"recovery_codes,omitempty"`
 }
@@ -0,0 +1,86 @@
+package routes
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mayurvarma14/go-movie-review/controllers"
+	"github.com/mayurvarma14/go-movie-review/internals/config"
+	"github.com/mayurvarma14/go-movie-review/middleware"
+)
+
+const V1Prefix = "/api/v1"
+
+// v1Released is when /api/v1 went live and the unversioned paths became
+// deprecated aliases.
+var v1Released = time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+
+// Handlers holds what an API version needs to mount its routes.
+type Handlers struct {
+	Users         *controllers.UserController
+	Sessions      *controllers.SessionController
+	Genres        *controllers.GenreController
+	Movies        *controllers.MovieController
+	Reviews       *controllers.ReviewController
+	Audit         *controllers.AuditController
+	Auth          *middleware.Authenticator
+	Limiter       *middleware.RateLimiter
+	SearchTimeout time.Duration
+	ExportTimeout time.Duration
+	// ImportMaxBodyBytes replaces the global body limit on the import routes.
+	ImportMaxBodyBytes int64
+}
+
+// APIRoutes mounts every API version under /api. Each version gets its own
+// function and group, so a v2 with different controllers and response shapes
+// can sit next to v1 without changing it.
+func APIRoutes(router *gin.Engine, h Handlers, cfg config.ServerConfig) {
+	api := router.Group("/api")
+	api.GET("", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "Welcome to the movie review API"})
+	})
+
+	V1(router.Group(V1Prefix), h)
+
+	if cfg.LegacyRoutes {
+		legacyV0(router.Group("", middleware.Deprecated(v1Released, cfg.LegacyRoutesSunset, V1Prefix)), h)
+	}
+}
+
+// V1 mounts version 1 of the resource routes on router.
+func V1(router gin.IRouter, h Handlers) {
+	legacyV0(router, h)
+	GenreV1Routes(router, h.Genres, h.Auth, h.Limiter, h.ExportTimeout, h.ImportMaxBodyBytes)
+	MovieV1Routes(router, h.Movies, h.Auth, h.Limiter, h.ExportTimeout, h.ImportMaxBodyBytes)
+	ReviewV1Routes(router, h.Reviews, h.Auth, h.Limiter, h.ExportTimeout)
+	AuditRoutes(router, h.Audit, h.Auth, h.Limiter)
+}
+
+// legacyV0 mounts the routes that existed before /api/v1, which are all the
+// unversioned aliases cover. Endpoints added since exist only under /api/v1.
+func legacyV0(router gin.IRouter, h Handlers) {
+	AuthRoutes(router, h.Users, h.Limiter)
+	SessionRoutes(router, h.Sessions, h.Auth, h.Limiter)
+	UserRoutes(router, h.Users, h.Auth, h.Limiter)
+	GenreRoutes(router, h.Genres, h.Auth, h.Limiter)
+	MovieRoutes(router, h.Movies, h.Auth, h.Limiter, h.SearchTimeout)
+	ReviewRoutes(router, h.Reviews, h.Auth, h.Limiter)
+}
+
+// Documented drops the deprecated aliases, which the API document describes
+// only through their /api/v1 counterparts.
+func Documented(all gin.RoutesInfo) gin.RoutesInfo {
+	registered := make(map[string]bool, len(all))
+	for _, route := range all {
+		registered[route.Method+" "+route.Path] = true
+	}
+
+	var documented gin.RoutesInfo
+	for _, route := range all {
+		if !registered[route.Method+" "+V1Prefix+route.Path] {
+			documented = append(documented, route)
+		}
+	}
+	return documented
+}
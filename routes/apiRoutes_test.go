@@ -0,0 +1,44 @@
+package routes_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mayurvarma14/go-movie-review/internals/config"
+	"github.com/mayurvarma14/go-movie-review/routes"
+)
+
+func TestLegacyAliasesOnlyPredateV1(t *testing.T) {
+	cfg := config.Default()
+	cfg.Server.LegacyRoutes = true
+	registered := map[string]bool{}
+	for _, route := range newRouter(t, cfg).Routes() {
+		registered[route.Method+" "+route.Path] = true
+	}
+
+	tests := []struct {
+		route string
+		alias bool
+	}{
+		{"POST /users/login", true},
+		{"GET /movies", true},
+		{"PUT /movies/:movie_id", true},
+		{"GET /reviews/filter", true},
+		{"PATCH /movies/:movie_id", false},
+		{"PATCH /genres/:genre_id", false},
+		{"GET /movies/trash", false},
+		{"POST /genres/:genre_id/restore", false},
+		{"POST /movies/import", false},
+		{"GET /reviews/export", false},
+		{"GET /audit", false},
+	}
+	for _, tt := range tests {
+		method, path, _ := strings.Cut(tt.route, " ")
+		if !registered[method+" "+routes.V1Prefix+path] {
+			t.Errorf("%s is not served under %s", tt.route, routes.V1Prefix)
+		}
+		if registered[tt.route] != tt.alias {
+			t.Errorf("%s: unversioned alias registered = %v, want %v", tt.route, registered[tt.route], tt.alias)
+		}
+	}
+}
@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mayurvarma14/go-movie-review/controllers"
+	"github.com/mayurvarma14/go-movie-review/middleware"
+)
+
+func AuditRoutes(router gin.IRouter, ac *controllers.AuditController, auth *middleware.Authenticator, limiter *middleware.RateLimiter) {
+	authed := router.Group("", auth.AuthenticateUser(), limiter.Limit(middleware.RateLimitDefault))
+	authed.GET("/audit", ac.GetAuditLog()) // List administrative actions (admin only)
+}
@@ -3,9 +3,13 @@ package routes
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/mayurvarma14/go-movie-review/controllers"
+	"github.com/mayurvarma14/go-movie-review/middleware"
 )
 
-func AuthRoutes(router *gin.Engine, uc *controllers.UserController) {
-	router.POST("/users/signup", uc.SignUp())
-	router.POST("/users/login", uc.Login())
+func AuthRoutes(router gin.IRouter, uc *controllers.UserController, limiter *middleware.RateLimiter) {
+	public := router.Group("", limiter.Limit(middleware.RateLimitAuth))
+	public.POST("/users/signup", uc.SignUp())
+	public.POST("/users/login", uc.Login())
+	public.POST("/users/refresh", uc.RefreshTokens())    // Exchange a refresh token for new tokens
+	public.POST("/users/login/mfa", uc.VerifyMFALogin()) // Complete login with a TOTP or recovery code
 }
@@ -0,0 +1,11 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mayurvarma14/go-movie-review/docs"
+)
+
+func DocsRoutes(router *gin.Engine) {
+	router.GET("/openapi.json", docs.Spec()) // OpenAPI 3 description of the API
+	router.GET("/docs/*filepath", docs.UI()) // Swagger UI
+}
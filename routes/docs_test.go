@@ -0,0 +1,43 @@
+package routes_test
+
+import (
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/mayurvarma14/go-movie-review/controllers"
+	"github.com/mayurvarma14/go-movie-review/database"
+	"github.com/mayurvarma14/go-movie-review/docs"
+	"github.com/mayurvarma14/go-movie-review/internals/config"
+	"github.com/mayurvarma14/go-movie-review/internals/server"
+	"github.com/mayurvarma14/go-movie-review/routes"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// newRouter builds the server's router. The mongo client never dials:
+// connecting is lazy and no handler runs.
+func newRouter(t *testing.T, cfg config.Config) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	client, err := mongo.Connect(options.Client().ApplyURI("mongodb://127.0.0.1:1"))
+	if err != nil {
+		t.Fatalf("creating mongo client: %v", err)
+	}
+	db := &database.Database{Client: client, Name: "test"}
+	cfg.Auth.SecretKey = "test-secret"
+
+	return server.NewRouter(&cfg, db, controllers.NewHealthController(db, &cfg))
+}
+
+func TestOpenAPICoversRoutes(t *testing.T) {
+	for _, legacy := range []bool{true, false} {
+		cfg := config.Default()
+		cfg.Server.LegacyRoutes = legacy
+
+		router := newRouter(t, cfg)
+		if err := docs.CheckCoverage(routes.Documented(router.Routes())); err != nil {
+			t.Errorf("legacy routes %v: %v", legacy, err)
+		}
+	}
+}
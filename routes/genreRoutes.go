@@ -1,16 +1,30 @@
 package routes
 
 import (
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"github.com/mayurvarma14/go-movie-review/controllers"
 	"github.com/mayurvarma14/go-movie-review/middleware"
 )
 
-func GenreRoutes(router *gin.Engine, gc *controllers.GenreController) {
-	router.Use(middleware.AuthenticateUser())
-	router.POST("/genres", gc.CreateGenre())             // Create a new genre (admin only)
-	router.GET("/genres/:genre_id", gc.GetGenre())       // Get a specific genre
-	router.GET("/genres", gc.GetGenres())                // Get all genres
-	router.PUT("/genres/:genre_id", gc.EditGenre())      // Update a genre (admin only)
-	router.DELETE("/genres/:genre_id", gc.DeleteGenre()) // Delete a genre (admin only)
+// GenreRoutes mounts the genre routes that predate /api/v1, which also have
+// unversioned aliases.
+func GenreRoutes(router gin.IRouter, gc *controllers.GenreController, auth *middleware.Authenticator, limiter *middleware.RateLimiter) {
+	authed := router.Group("", auth.AuthenticateUser(), limiter.Limit(middleware.RateLimitDefault))
+	authed.POST("/genres", gc.CreateGenre())             // Create a new genre (admin only)
+	authed.GET("/genres/:genre_id", gc.GetGenre())       // Get a specific genre
+	authed.GET("/genres", gc.GetGenres())                // Get all genres
+	authed.PUT("/genres/:genre_id", gc.EditGenre())      // Update a genre (admin only)
+	authed.DELETE("/genres/:genre_id", gc.DeleteGenre()) // Move a genre to the trash (admin only)
+}
+
+// GenreV1Routes mounts the genre routes added since /api/v1.
+func GenreV1Routes(router gin.IRouter, gc *controllers.GenreController, auth *middleware.Authenticator, limiter *middleware.RateLimiter, exportTimeout time.Duration, importMaxBodyBytes int64) {
+	authed := router.Group("", auth.AuthenticateUser(), limiter.Limit(middleware.RateLimitDefault))
+	authed.PATCH("/genres/:genre_id", gc.PatchGenre())                                         // Partially update a genre (admin only)
+	authed.GET("/genres/trash", gc.GetDeletedGenres())                                         // List genres in the trash (admin only)
+	authed.POST("/genres/:genre_id/restore", gc.RestoreGenre())                                // Restore a genre from the trash (admin only)
+	authed.GET("/genres/export", middleware.Timeout(exportTimeout), gc.ExportGenres())         // Download genres as CSV, JSON or NDJSON (admin only)
+	authed.POST("/genres/import", middleware.BodyLimit(importMaxBodyBytes), gc.ImportGenres()) // Create and update genres from CSV or NDJSON (admin only)
 }
@@ -0,0 +1,11 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mayurvarma14/go-movie-review/controllers"
+)
+
+func HealthRoutes(router *gin.Engine, hc *controllers.HealthController) {
+	router.GET("/healthz", hc.Liveness()) // Process is up
+	router.GET("/readyz", hc.Readiness()) // Dependencies are ready to serve traffic
+}
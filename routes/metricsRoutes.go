@@ -0,0 +1,11 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mayurvarma14/go-movie-review/internals/metrics"
+	"github.com/mayurvarma14/go-movie-review/middleware"
+)
+
+func MetricsRoutes(router *gin.Engine, token string) {
+	router.GET("/metrics", middleware.MetricsAuth(token), gin.WrapH(metrics.Handler())) // Prometheus scrape endpoint
+}
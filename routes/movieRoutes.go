@@ -1,18 +1,35 @@
 package routes
 
 import (
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"github.com/mayurvarma14/go-movie-review/controllers"
 	"github.com/mayurvarma14/go-movie-review/middleware"
 )
 
-func MovieRoutes(router *gin.Engine, mc *controllers.MovieController) {
-	router.Use(middleware.AuthenticateUser())
-	router.POST("/movies", mc.CreateMovie())              // Create a new movie (admin only)
-	router.GET("/movies/:movie_id", mc.GetMovie())        // Get a specific movie
-	router.GET("/movies", mc.GetMovies())                 // Get all movies
-	router.PUT("/movies/:movie_id", mc.UpdateMovie())     // Update a movie (admin only)
-	router.GET("/movies/search", mc.SearchMovieByQuery()) // Search movies by name
-	router.GET("/movies/filter", mc.SearchMovieByGenre()) // Search movies by genre
-	router.DELETE("/movies/:movie_id", mc.DeleteMovie())  // Delete a movie (admin only)
+// MovieRoutes mounts the movie routes that predate /api/v1, which also have
+// unversioned aliases.
+func MovieRoutes(router gin.IRouter, mc *controllers.MovieController, auth *middleware.Authenticator, limiter *middleware.RateLimiter, searchTimeout time.Duration) {
+	searchDeadline := middleware.Timeout(searchTimeout)
+	searchLimit := limiter.Limit(middleware.RateLimitSearch)
+
+	authed := router.Group("", auth.AuthenticateUser(), limiter.Limit(middleware.RateLimitDefault))
+	authed.POST("/movies", mc.CreateMovie())                                           // Create a new movie (admin only)
+	authed.GET("/movies/:movie_id", mc.GetMovie())                                     // Get a specific movie
+	authed.GET("/movies", mc.GetMovies())                                              // Get all movies
+	authed.PUT("/movies/:movie_id", mc.UpdateMovie())                                  // Update a movie (admin only)
+	authed.GET("/movies/search", searchDeadline, searchLimit, mc.SearchMovieByQuery()) // Search movies by name
+	authed.GET("/movies/filter", mc.SearchMovieByGenre())                              // Search movies by genre
+	authed.DELETE("/movies/:movie_id", mc.DeleteMovie())                               // Move a movie to the trash (admin only)
+}
+
+// MovieV1Routes mounts the movie routes added since /api/v1.
+func MovieV1Routes(router gin.IRouter, mc *controllers.MovieController, auth *middleware.Authenticator, limiter *middleware.RateLimiter, exportTimeout time.Duration, importMaxBodyBytes int64) {
+	authed := router.Group("", auth.AuthenticateUser(), limiter.Limit(middleware.RateLimitDefault))
+	authed.PATCH("/movies/:movie_id", mc.PatchMovie())                                         // Partially update a movie (admin only)
+	authed.GET("/movies/trash", mc.GetDeletedMovies())                                         // List movies in the trash (admin only)
+	authed.POST("/movies/:movie_id/restore", mc.RestoreMovie())                                // Restore a movie from the trash (admin only)
+	authed.GET("/movies/export", middleware.Timeout(exportTimeout), mc.ExportMovies())         // Download movies as CSV, JSON or NDJSON (admin only)
+	authed.POST("/movies/import", middleware.BodyLimit(importMaxBodyBytes), mc.ImportMovies()) // Create and update movies from CSV or NDJSON (admin only)
 }
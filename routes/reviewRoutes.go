@@ -1,15 +1,27 @@
 package routes
 
 import (
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"github.com/mayurvarma14/go-movie-review/controllers"
 	"github.com/mayurvarma14/go-movie-review/middleware"
 )
 
-func ReviewRoutes(router *gin.Engine, rc *controllers.ReviewController) {
-	router.Use(middleware.AuthenticateUser())
-	router.POST("/reviews", rc.AddReview())                       // Add a review (user only)
-	router.GET("/reviews/filter", rc.ViewAMovieReviews())         // Get reviews for a movie
-	router.DELETE("/reviews/:id", rc.DeleteReview())              // Delete a review (owner or admin)
-	router.GET("/reviews/user/:reviewer_id", rc.AllUserReviews()) // Get all reviews by a user
+// ReviewRoutes mounts the review routes that predate /api/v1, which also have
+// unversioned aliases.
+func ReviewRoutes(router gin.IRouter, rc *controllers.ReviewController, auth *middleware.Authenticator, limiter *middleware.RateLimiter) {
+	authed := router.Group("", auth.AuthenticateUser(), limiter.Limit(middleware.RateLimitDefault))
+	authed.POST("/reviews", limiter.Limit(middleware.RateLimitReviews), rc.AddReview()) // Add a review (user only)
+	authed.GET("/reviews/filter", rc.ViewAMovieReviews())                               // Get reviews for a movie
+	authed.DELETE("/reviews/:id", rc.DeleteReview())                                    // Move a review to the trash (owner or admin)
+	authed.GET("/reviews/user/:reviewer_id", rc.AllUserReviews())                       // Get all reviews by a user
+}
+
+// ReviewV1Routes mounts the review routes added since /api/v1.
+func ReviewV1Routes(router gin.IRouter, rc *controllers.ReviewController, auth *middleware.Authenticator, limiter *middleware.RateLimiter, exportTimeout time.Duration) {
+	authed := router.Group("", auth.AuthenticateUser(), limiter.Limit(middleware.RateLimitDefault))
+	authed.GET("/reviews/trash", rc.GetDeletedReviews())                                 // List reviews in the trash (admin only)
+	authed.POST("/reviews/:id/restore", rc.RestoreReview())                              // Restore a review from the trash (admin only)
+	authed.GET("/reviews/export", middleware.Timeout(exportTimeout), rc.ExportReviews()) // Download reviews as CSV, JSON or NDJSON (admin only)
 }
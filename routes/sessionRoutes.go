@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/mayurvarma14/go-movie-review/controllers"
+	"github.com/mayurvarma14/go-movie-review/middleware"
+)
+
+func SessionRoutes(router gin.IRouter, sc *controllers.SessionController, auth *middleware.Authenticator, limiter *middleware.RateLimiter) {
+	authed := router.Group("", auth.AuthenticateUser(), limiter.Limit(middleware.RateLimitDefault))
+	authed.GET("/users/me/sessions", sc.GetMySessions())          // List the caller's active sessions
+	authed.DELETE("/users/me/sessions/:id", sc.RevokeMySession()) // Sign out a session
+}
@@ -6,8 +6,13 @@ import (
 	"github.com/mayurvarma14/go-movie-review/middleware"
 )
 
-func UserRoutes(router *gin.Engine, uc *controllers.UserController) {
-	router.Use(middleware.AuthenticateUser())
-	router.GET("/users/:user_id", uc.GetUser()) // Get a specific user
-	router.GET("/users", uc.GetUsers())         // Get all users (admin only)
+func UserRoutes(router gin.IRouter, uc *controllers.UserController, auth *middleware.Authenticator, limiter *middleware.RateLimiter) {
+	authed := router.Group("", auth.AuthenticateUser(), limiter.Limit(middleware.RateLimitDefault))
+	authed.POST("/users/mfa/enroll", uc.EnrollMFA())                       // Start TOTP enrollment
+	authed.POST("/users/mfa/confirm", uc.ConfirmMFA())                     // Confirm enrollment and get recovery codes
+	authed.POST("/users/mfa/disable", uc.DisableMFA())                     // Disable MFA
+	authed.POST("/users/mfa/recovery-codes", uc.RegenerateRecoveryCodes()) // Regenerate recovery codes
+	authed.PUT("/users/mfa/policy", uc.SetMFAPolicy())                     // Require MFA for admins (admin only)
+	authed.GET("/users/:user_id", uc.GetUser())                            // Get a specific user
+	authed.GET("/users", uc.GetUsers())                                    // Get all users (admin only)
 }